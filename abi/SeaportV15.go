@@ -0,0 +1,1198 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SeaportV15MetaData contains all meta data concerning the SeaportV15 contract.
+var SeaportV15MetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newCounter\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"}],\"name\":\"CounterIncremented\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"}],\"name\":\"OrderCancelled\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifier\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"indexed\":false,\"internalType\":\"structSpentItem[]\",\"name\":\"offer\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifier\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"}],\"indexed\":false,\"internalType\":\"structReceivedItem[]\",\"name\":\"consideration\",\"type\":\"tuple[]\"}],\"name\":\"OrderFulfilled\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"},{\"components\":[{\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"}],\"internalType\":\"structOfferItem[]\",\"name\":\"offer\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"}],\"internalType\":\"structConsiderationItem[]\",\"name\":\"consideration\",\"type\":\"tuple[]\"},{\"internalType\":\"enumOrderType\",\"name\":\"orderType\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"zoneHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"salt\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"conduitKey\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"totalOriginalConsiderationItems\",\"type\":\"uint256\"}],\"internalType\":\"structOrderParameters\",\"name\":\"orderParameters\",\"type\":\"tuple\",\"indexed\":false}],\"name\":\"OrderValidated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32[]\",\"name\":\"orderHashes\",\"type\":\"bytes32[]\"}],\"name\":\"OrdersMatched\",\"type\":\"event\"},{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"}],\"internalType\":\"structOfferItem[]\",\"name\":\"offer\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"}],\"internalType\":\"structConsiderationItem[]\",\"name\":\"consideration\",\"type\":\"tuple[]\"},{\"internalType\":\"enumOrderType\",\"name\":\"orderType\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"zoneHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"salt\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"conduitKey\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"counter\",\"type\":\"uint256\"}],\"internalType\":\"structOrderComponents[]\",\"name\":\"orders\",\"type\":\"tuple[]\"}],\"name\":\"cancel\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"cancelled\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"contractOfferer\",\"type\":\"address\"}],\"name\":\"getContractOffererNonce\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"nonce\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"}],\"name\":\"getCounter\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"counter\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"}],\"internalType\":\"structOfferItem[]\",\"name\":\"offer\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"}],\"internalType\":\"structConsiderationItem[]\",\"name\":\"consideration\",\"type\":\"tuple[]\"},{\"internalType\":\"enumOrderType\",\"name\":\"orderType\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"zoneHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"salt\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"conduitKey\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"counter\",\"type\":\"uint256\"}],\"internalType\":\"structOrderComponents\",\"name\":\"order\",\"type\":\"tuple\"}],\"name\":\"getOrderHash\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"}],\"name\":\"getOrderStatus\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"isValidated\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isCancelled\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"totalFilled\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalSize\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"incrementCounter\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"newCounter\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"information\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"version\",\"type\":\"string\"},{\"internalType\":\"bytes32\",\"name\":\"domainSeparator\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"conduitController\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"contractName\",\"type\":\"string\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"offerer\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"zone\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"}],\"internalType\":\"structOfferItem[]\",\"name\":\"offer\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"enumItemType\",\"name\":\"itemType\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"identifierOrCriteria\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endAmount\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"}],\"internalType\":\"structConsiderationItem[]\",\"name\":\"consideration\",\"type\":\"tuple[]\"},{\"internalType\":\"enumOrderType\",\"name\":\"orderType\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"zoneHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"salt\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"conduitKey\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"totalOriginalConsiderationItems\",\"type\":\"uint256\"}],\"internalType\":\"structOrderParameters\",\"name\":\"parameters\",\"type\":\"tuple\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"internalType\":\"structOrder[]\",\"name\":\"orders\",\"type\":\"tuple[]\"}],\"name\":\"validate\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"validated\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+}
+
+// SeaportV15ABI is the input ABI used to generate the binding from.
+// Deprecated: Use SeaportV15MetaData.ABI instead.
+var SeaportV15ABI = SeaportV15MetaData.ABI
+
+// SeaportV15 is an auto generated Go binding around an Ethereum contract.
+type SeaportV15 struct {
+	SeaportV15Caller     // Read-only binding to the contract
+	SeaportV15Transactor // Write-only binding to the contract
+	SeaportV15Filterer   // Log filterer for contract events
+}
+
+// SeaportV15Caller is an auto generated read-only Go binding around an Ethereum contract.
+type SeaportV15Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportV15Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type SeaportV15Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportV15Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SeaportV15Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportV15Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SeaportV15Session struct {
+	Contract     *SeaportV15       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SeaportV15CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SeaportV15CallerSession struct {
+	Contract *SeaportV15Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// SeaportV15TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SeaportV15TransactorSession struct {
+	Contract     *SeaportV15Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// SeaportV15Raw is an auto generated low-level Go binding around an Ethereum contract.
+type SeaportV15Raw struct {
+	Contract *SeaportV15 // Generic contract binding to access the raw methods on
+}
+
+// SeaportV15CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SeaportV15CallerRaw struct {
+	Contract *SeaportV15Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// SeaportV15TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SeaportV15TransactorRaw struct {
+	Contract *SeaportV15Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSeaportV15 creates a new instance of SeaportV15, bound to a specific deployed contract.
+func NewSeaportV15(address common.Address, backend bind.ContractBackend) (*SeaportV15, error) {
+	contract, err := bindSeaportV15(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15{SeaportV15Caller: SeaportV15Caller{contract: contract}, SeaportV15Transactor: SeaportV15Transactor{contract: contract}, SeaportV15Filterer: SeaportV15Filterer{contract: contract}}, nil
+}
+
+// NewSeaportV15Caller creates a new read-only instance of SeaportV15, bound to a specific deployed contract.
+func NewSeaportV15Caller(address common.Address, caller bind.ContractCaller) (*SeaportV15Caller, error) {
+	contract, err := bindSeaportV15(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15Caller{contract: contract}, nil
+}
+
+// NewSeaportV15Transactor creates a new write-only instance of SeaportV15, bound to a specific deployed contract.
+func NewSeaportV15Transactor(address common.Address, transactor bind.ContractTransactor) (*SeaportV15Transactor, error) {
+	contract, err := bindSeaportV15(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15Transactor{contract: contract}, nil
+}
+
+// NewSeaportV15Filterer creates a new log filterer instance of SeaportV15, bound to a specific deployed contract.
+func NewSeaportV15Filterer(address common.Address, filterer bind.ContractFilterer) (*SeaportV15Filterer, error) {
+	contract, err := bindSeaportV15(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15Filterer{contract: contract}, nil
+}
+
+// bindSeaportV15 binds a generic wrapper to an already deployed contract.
+func bindSeaportV15(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SeaportV15MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SeaportV15 *SeaportV15Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SeaportV15.Contract.SeaportV15Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SeaportV15 *SeaportV15Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SeaportV15.Contract.SeaportV15Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SeaportV15 *SeaportV15Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SeaportV15.Contract.SeaportV15Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SeaportV15 *SeaportV15CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SeaportV15.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SeaportV15 *SeaportV15TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SeaportV15.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SeaportV15 *SeaportV15TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SeaportV15.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetContractOffererNonce is a free data retrieval call binding the contract method 0xa900866b.
+//
+// Solidity: function getContractOffererNonce(address contractOfferer) view returns(uint256 nonce)
+func (_SeaportV15 *SeaportV15Caller) GetContractOffererNonce(opts *bind.CallOpts, contractOfferer common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "getContractOffererNonce", contractOfferer)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetContractOffererNonce is a free data retrieval call binding the contract method 0xa900866b.
+//
+// Solidity: function getContractOffererNonce(address contractOfferer) view returns(uint256 nonce)
+func (_SeaportV15 *SeaportV15Session) GetContractOffererNonce(contractOfferer common.Address) (*big.Int, error) {
+	return _SeaportV15.Contract.GetContractOffererNonce(&_SeaportV15.CallOpts, contractOfferer)
+}
+
+// GetContractOffererNonce is a free data retrieval call binding the contract method 0xa900866b.
+//
+// Solidity: function getContractOffererNonce(address contractOfferer) view returns(uint256 nonce)
+func (_SeaportV15 *SeaportV15CallerSession) GetContractOffererNonce(contractOfferer common.Address) (*big.Int, error) {
+	return _SeaportV15.Contract.GetContractOffererNonce(&_SeaportV15.CallOpts, contractOfferer)
+}
+
+// GetCounter is a free data retrieval call binding the contract method 0xf07ec373.
+//
+// Solidity: function getCounter(address offerer) view returns(uint256 counter)
+func (_SeaportV15 *SeaportV15Caller) GetCounter(opts *bind.CallOpts, offerer common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "getCounter", offerer)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetCounter is a free data retrieval call binding the contract method 0xf07ec373.
+//
+// Solidity: function getCounter(address offerer) view returns(uint256 counter)
+func (_SeaportV15 *SeaportV15Session) GetCounter(offerer common.Address) (*big.Int, error) {
+	return _SeaportV15.Contract.GetCounter(&_SeaportV15.CallOpts, offerer)
+}
+
+// GetCounter is a free data retrieval call binding the contract method 0xf07ec373.
+//
+// Solidity: function getCounter(address offerer) view returns(uint256 counter)
+func (_SeaportV15 *SeaportV15CallerSession) GetCounter(offerer common.Address) (*big.Int, error) {
+	return _SeaportV15.Contract.GetCounter(&_SeaportV15.CallOpts, offerer)
+}
+
+// GetOrderHash is a free data retrieval call binding the contract method 0x79df72bd.
+//
+// Solidity: function getOrderHash((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) order) view returns(bytes32 orderHash)
+func (_SeaportV15 *SeaportV15Caller) GetOrderHash(opts *bind.CallOpts, order OrderComponents) ([32]byte, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "getOrderHash", order)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetOrderHash is a free data retrieval call binding the contract method 0x79df72bd.
+//
+// Solidity: function getOrderHash((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) order) view returns(bytes32 orderHash)
+func (_SeaportV15 *SeaportV15Session) GetOrderHash(order OrderComponents) ([32]byte, error) {
+	return _SeaportV15.Contract.GetOrderHash(&_SeaportV15.CallOpts, order)
+}
+
+// GetOrderHash is a free data retrieval call binding the contract method 0x79df72bd.
+//
+// Solidity: function getOrderHash((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) order) view returns(bytes32 orderHash)
+func (_SeaportV15 *SeaportV15CallerSession) GetOrderHash(order OrderComponents) ([32]byte, error) {
+	return _SeaportV15.Contract.GetOrderHash(&_SeaportV15.CallOpts, order)
+}
+
+// GetOrderStatus is a free data retrieval call binding the contract method 0x46423aa7.
+//
+// Solidity: function getOrderStatus(bytes32 orderHash) view returns(bool isValidated, bool isCancelled, uint256 totalFilled, uint256 totalSize)
+func (_SeaportV15 *SeaportV15Caller) GetOrderStatus(opts *bind.CallOpts, orderHash [32]byte) (struct {
+	IsValidated bool
+	IsCancelled bool
+	TotalFilled *big.Int
+	TotalSize   *big.Int
+}, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "getOrderStatus", orderHash)
+
+	outstruct := new(struct {
+		IsValidated bool
+		IsCancelled bool
+		TotalFilled *big.Int
+		TotalSize   *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.IsValidated = *abi.ConvertType(out[0], new(bool)).(*bool)
+	outstruct.IsCancelled = *abi.ConvertType(out[1], new(bool)).(*bool)
+	outstruct.TotalFilled = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.TotalSize = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetOrderStatus is a free data retrieval call binding the contract method 0x46423aa7.
+//
+// Solidity: function getOrderStatus(bytes32 orderHash) view returns(bool isValidated, bool isCancelled, uint256 totalFilled, uint256 totalSize)
+func (_SeaportV15 *SeaportV15Session) GetOrderStatus(orderHash [32]byte) (struct {
+	IsValidated bool
+	IsCancelled bool
+	TotalFilled *big.Int
+	TotalSize   *big.Int
+}, error) {
+	return _SeaportV15.Contract.GetOrderStatus(&_SeaportV15.CallOpts, orderHash)
+}
+
+// GetOrderStatus is a free data retrieval call binding the contract method 0x46423aa7.
+//
+// Solidity: function getOrderStatus(bytes32 orderHash) view returns(bool isValidated, bool isCancelled, uint256 totalFilled, uint256 totalSize)
+func (_SeaportV15 *SeaportV15CallerSession) GetOrderStatus(orderHash [32]byte) (struct {
+	IsValidated bool
+	IsCancelled bool
+	TotalFilled *big.Int
+	TotalSize   *big.Int
+}, error) {
+	return _SeaportV15.Contract.GetOrderStatus(&_SeaportV15.CallOpts, orderHash)
+}
+
+// Information is a free data retrieval call binding the contract method 0xf47b7740.
+//
+// Solidity: function information() view returns(string version, bytes32 domainSeparator, address conduitController)
+func (_SeaportV15 *SeaportV15Caller) Information(opts *bind.CallOpts) (struct {
+	Version           string
+	DomainSeparator   [32]byte
+	ConduitController common.Address
+}, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "information")
+
+	outstruct := new(struct {
+		Version           string
+		DomainSeparator   [32]byte
+		ConduitController common.Address
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Version = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.DomainSeparator = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.ConduitController = *abi.ConvertType(out[2], new(common.Address)).(*common.Address)
+
+	return *outstruct, err
+
+}
+
+// Information is a free data retrieval call binding the contract method 0xf47b7740.
+//
+// Solidity: function information() view returns(string version, bytes32 domainSeparator, address conduitController)
+func (_SeaportV15 *SeaportV15Session) Information() (struct {
+	Version           string
+	DomainSeparator   [32]byte
+	ConduitController common.Address
+}, error) {
+	return _SeaportV15.Contract.Information(&_SeaportV15.CallOpts)
+}
+
+// Information is a free data retrieval call binding the contract method 0xf47b7740.
+//
+// Solidity: function information() view returns(string version, bytes32 domainSeparator, address conduitController)
+func (_SeaportV15 *SeaportV15CallerSession) Information() (struct {
+	Version           string
+	DomainSeparator   [32]byte
+	ConduitController common.Address
+}, error) {
+	return _SeaportV15.Contract.Information(&_SeaportV15.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() pure returns(string contractName)
+func (_SeaportV15 *SeaportV15Caller) Name(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _SeaportV15.contract.Call(opts, &out, "name")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() pure returns(string contractName)
+func (_SeaportV15 *SeaportV15Session) Name() (string, error) {
+	return _SeaportV15.Contract.Name(&_SeaportV15.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() pure returns(string contractName)
+func (_SeaportV15 *SeaportV15CallerSession) Name() (string, error) {
+	return _SeaportV15.Contract.Name(&_SeaportV15.CallOpts)
+}
+
+// Cancel is a paid mutator transaction binding the contract method 0xfd9f1e10.
+//
+// Solidity: function cancel((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256)[] orders) returns(bool cancelled)
+func (_SeaportV15 *SeaportV15Transactor) Cancel(opts *bind.TransactOpts, orders []OrderComponents) (*types.Transaction, error) {
+	return _SeaportV15.contract.Transact(opts, "cancel", orders)
+}
+
+// Cancel is a paid mutator transaction binding the contract method 0xfd9f1e10.
+//
+// Solidity: function cancel((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256)[] orders) returns(bool cancelled)
+func (_SeaportV15 *SeaportV15Session) Cancel(orders []OrderComponents) (*types.Transaction, error) {
+	return _SeaportV15.Contract.Cancel(&_SeaportV15.TransactOpts, orders)
+}
+
+// Cancel is a paid mutator transaction binding the contract method 0xfd9f1e10.
+//
+// Solidity: function cancel((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256)[] orders) returns(bool cancelled)
+func (_SeaportV15 *SeaportV15TransactorSession) Cancel(orders []OrderComponents) (*types.Transaction, error) {
+	return _SeaportV15.Contract.Cancel(&_SeaportV15.TransactOpts, orders)
+}
+
+// IncrementCounter is a paid mutator transaction binding the contract method 0x5b34b966.
+//
+// Solidity: function incrementCounter() returns(uint256 newCounter)
+func (_SeaportV15 *SeaportV15Transactor) IncrementCounter(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SeaportV15.contract.Transact(opts, "incrementCounter")
+}
+
+// IncrementCounter is a paid mutator transaction binding the contract method 0x5b34b966.
+//
+// Solidity: function incrementCounter() returns(uint256 newCounter)
+func (_SeaportV15 *SeaportV15Session) IncrementCounter() (*types.Transaction, error) {
+	return _SeaportV15.Contract.IncrementCounter(&_SeaportV15.TransactOpts)
+}
+
+// IncrementCounter is a paid mutator transaction binding the contract method 0x5b34b966.
+//
+// Solidity: function incrementCounter() returns(uint256 newCounter)
+func (_SeaportV15 *SeaportV15TransactorSession) IncrementCounter() (*types.Transaction, error) {
+	return _SeaportV15.Contract.IncrementCounter(&_SeaportV15.TransactOpts)
+}
+
+// Validate is a paid mutator transaction binding the contract method 0x88147732.
+//
+// Solidity: function validate(((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256),bytes)[] orders) returns(bool validated)
+func (_SeaportV15 *SeaportV15Transactor) Validate(opts *bind.TransactOpts, orders []Order) (*types.Transaction, error) {
+	return _SeaportV15.contract.Transact(opts, "validate", orders)
+}
+
+// Validate is a paid mutator transaction binding the contract method 0x88147732.
+//
+// Solidity: function validate(((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256),bytes)[] orders) returns(bool validated)
+func (_SeaportV15 *SeaportV15Session) Validate(orders []Order) (*types.Transaction, error) {
+	return _SeaportV15.Contract.Validate(&_SeaportV15.TransactOpts, orders)
+}
+
+// Validate is a paid mutator transaction binding the contract method 0x88147732.
+//
+// Solidity: function validate(((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256),bytes)[] orders) returns(bool validated)
+func (_SeaportV15 *SeaportV15TransactorSession) Validate(orders []Order) (*types.Transaction, error) {
+	return _SeaportV15.Contract.Validate(&_SeaportV15.TransactOpts, orders)
+}
+
+// SeaportV15CounterIncrementedIterator is returned from FilterCounterIncremented and is used to iterate over the raw logs and unpacked data for CounterIncremented events raised by the SeaportV15 contract.
+type SeaportV15CounterIncrementedIterator struct {
+	Event *SeaportV15CounterIncremented // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportV15CounterIncrementedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportV15CounterIncremented)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportV15CounterIncremented)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportV15CounterIncrementedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportV15CounterIncrementedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportV15CounterIncremented represents a CounterIncremented event raised by the SeaportV15 contract.
+type SeaportV15CounterIncremented struct {
+	NewCounter *big.Int
+	Offerer    common.Address
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterCounterIncremented is a free log retrieval operation binding the contract event 0x721c20121297512b72821b97f5326877ea8ecf4bb9948fea5bfcb6453074d37f.
+//
+// Solidity: event CounterIncremented(uint256 newCounter, address indexed offerer)
+func (_SeaportV15 *SeaportV15Filterer) FilterCounterIncremented(opts *bind.FilterOpts, offerer []common.Address) (*SeaportV15CounterIncrementedIterator, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.FilterLogs(opts, "CounterIncremented", offererRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15CounterIncrementedIterator{contract: _SeaportV15.contract, event: "CounterIncremented", logs: logs, sub: sub}, nil
+}
+
+// WatchCounterIncremented is a free log subscription operation binding the contract event 0x721c20121297512b72821b97f5326877ea8ecf4bb9948fea5bfcb6453074d37f.
+//
+// Solidity: event CounterIncremented(uint256 newCounter, address indexed offerer)
+func (_SeaportV15 *SeaportV15Filterer) WatchCounterIncremented(opts *bind.WatchOpts, sink chan<- *SeaportV15CounterIncremented, offerer []common.Address) (event.Subscription, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.WatchLogs(opts, "CounterIncremented", offererRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportV15CounterIncremented)
+				if err := _SeaportV15.contract.UnpackLog(event, "CounterIncremented", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCounterIncremented is a log parse operation binding the contract event 0x721c20121297512b72821b97f5326877ea8ecf4bb9948fea5bfcb6453074d37f.
+//
+// Solidity: event CounterIncremented(uint256 newCounter, address indexed offerer)
+func (_SeaportV15 *SeaportV15Filterer) ParseCounterIncremented(log types.Log) (*SeaportV15CounterIncremented, error) {
+	event := new(SeaportV15CounterIncremented)
+	if err := _SeaportV15.contract.UnpackLog(event, "CounterIncremented", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SeaportV15OrderCancelledIterator is returned from FilterOrderCancelled and is used to iterate over the raw logs and unpacked data for OrderCancelled events raised by the SeaportV15 contract.
+type SeaportV15OrderCancelledIterator struct {
+	Event *SeaportV15OrderCancelled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportV15OrderCancelledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportV15OrderCancelled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportV15OrderCancelled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportV15OrderCancelledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportV15OrderCancelledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportV15OrderCancelled represents a OrderCancelled event raised by the SeaportV15 contract.
+type SeaportV15OrderCancelled struct {
+	OrderHash [32]byte
+	Offerer   common.Address
+	Zone      common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrderCancelled is a free log retrieval operation binding the contract event 0x6bacc01dbe442496068f7d234edd811f1a5f833243e0aec824f86ab861f3c90d.
+//
+// Solidity: event OrderCancelled(bytes32 orderHash, address indexed offerer, address indexed zone)
+func (_SeaportV15 *SeaportV15Filterer) FilterOrderCancelled(opts *bind.FilterOpts, offerer []common.Address, zone []common.Address) (*SeaportV15OrderCancelledIterator, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.FilterLogs(opts, "OrderCancelled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15OrderCancelledIterator{contract: _SeaportV15.contract, event: "OrderCancelled", logs: logs, sub: sub}, nil
+}
+
+// WatchOrderCancelled is a free log subscription operation binding the contract event 0x6bacc01dbe442496068f7d234edd811f1a5f833243e0aec824f86ab861f3c90d.
+//
+// Solidity: event OrderCancelled(bytes32 orderHash, address indexed offerer, address indexed zone)
+func (_SeaportV15 *SeaportV15Filterer) WatchOrderCancelled(opts *bind.WatchOpts, sink chan<- *SeaportV15OrderCancelled, offerer []common.Address, zone []common.Address) (event.Subscription, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.WatchLogs(opts, "OrderCancelled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportV15OrderCancelled)
+				if err := _SeaportV15.contract.UnpackLog(event, "OrderCancelled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrderCancelled is a log parse operation binding the contract event 0x6bacc01dbe442496068f7d234edd811f1a5f833243e0aec824f86ab861f3c90d.
+//
+// Solidity: event OrderCancelled(bytes32 orderHash, address indexed offerer, address indexed zone)
+func (_SeaportV15 *SeaportV15Filterer) ParseOrderCancelled(log types.Log) (*SeaportV15OrderCancelled, error) {
+	event := new(SeaportV15OrderCancelled)
+	if err := _SeaportV15.contract.UnpackLog(event, "OrderCancelled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SeaportV15OrderFulfilledIterator is returned from FilterOrderFulfilled and is used to iterate over the raw logs and unpacked data for OrderFulfilled events raised by the SeaportV15 contract.
+type SeaportV15OrderFulfilledIterator struct {
+	Event *SeaportV15OrderFulfilled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportV15OrderFulfilledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportV15OrderFulfilled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportV15OrderFulfilled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportV15OrderFulfilledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportV15OrderFulfilledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportV15OrderFulfilled represents a OrderFulfilled event raised by the SeaportV15 contract.
+type SeaportV15OrderFulfilled struct {
+	OrderHash     [32]byte
+	Offerer       common.Address
+	Zone          common.Address
+	Recipient     common.Address
+	Offer         []SpentItem
+	Consideration []ReceivedItem
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrderFulfilled is a free log retrieval operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_SeaportV15 *SeaportV15Filterer) FilterOrderFulfilled(opts *bind.FilterOpts, offerer []common.Address, zone []common.Address) (*SeaportV15OrderFulfilledIterator, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.FilterLogs(opts, "OrderFulfilled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15OrderFulfilledIterator{contract: _SeaportV15.contract, event: "OrderFulfilled", logs: logs, sub: sub}, nil
+}
+
+// WatchOrderFulfilled is a free log subscription operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_SeaportV15 *SeaportV15Filterer) WatchOrderFulfilled(opts *bind.WatchOpts, sink chan<- *SeaportV15OrderFulfilled, offerer []common.Address, zone []common.Address) (event.Subscription, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _SeaportV15.contract.WatchLogs(opts, "OrderFulfilled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportV15OrderFulfilled)
+				if err := _SeaportV15.contract.UnpackLog(event, "OrderFulfilled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrderFulfilled is a log parse operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_SeaportV15 *SeaportV15Filterer) ParseOrderFulfilled(log types.Log) (*SeaportV15OrderFulfilled, error) {
+	event := new(SeaportV15OrderFulfilled)
+	if err := _SeaportV15.contract.UnpackLog(event, "OrderFulfilled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SeaportV15OrderValidatedIterator is returned from FilterOrderValidated and is used to iterate over the raw logs and unpacked data for OrderValidated events raised by the SeaportV15 contract.
+type SeaportV15OrderValidatedIterator struct {
+	Event *SeaportV15OrderValidated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportV15OrderValidatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportV15OrderValidated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportV15OrderValidated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportV15OrderValidatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportV15OrderValidatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportV15OrderValidated represents a OrderValidated event raised by the SeaportV15 contract.
+type SeaportV15OrderValidated struct {
+	OrderHash       [32]byte
+	OrderParameters OrderParameters
+	Raw             types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrderValidated is a free log retrieval operation binding the contract event 0xf280791efe782edcf06ce15c8f4dff17601db3b88eb3805a0db7d77faf757f04.
+//
+// Solidity: event OrderValidated(bytes32 orderHash, (address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) orderParameters)
+func (_SeaportV15 *SeaportV15Filterer) FilterOrderValidated(opts *bind.FilterOpts) (*SeaportV15OrderValidatedIterator, error) {
+
+	logs, sub, err := _SeaportV15.contract.FilterLogs(opts, "OrderValidated")
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15OrderValidatedIterator{contract: _SeaportV15.contract, event: "OrderValidated", logs: logs, sub: sub}, nil
+}
+
+// WatchOrderValidated is a free log subscription operation binding the contract event 0xf280791efe782edcf06ce15c8f4dff17601db3b88eb3805a0db7d77faf757f04.
+//
+// Solidity: event OrderValidated(bytes32 orderHash, (address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) orderParameters)
+func (_SeaportV15 *SeaportV15Filterer) WatchOrderValidated(opts *bind.WatchOpts, sink chan<- *SeaportV15OrderValidated) (event.Subscription, error) {
+
+	logs, sub, err := _SeaportV15.contract.WatchLogs(opts, "OrderValidated")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportV15OrderValidated)
+				if err := _SeaportV15.contract.UnpackLog(event, "OrderValidated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrderValidated is a log parse operation binding the contract event 0xf280791efe782edcf06ce15c8f4dff17601db3b88eb3805a0db7d77faf757f04.
+//
+// Solidity: event OrderValidated(bytes32 orderHash, (address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256) orderParameters)
+func (_SeaportV15 *SeaportV15Filterer) ParseOrderValidated(log types.Log) (*SeaportV15OrderValidated, error) {
+	event := new(SeaportV15OrderValidated)
+	if err := _SeaportV15.contract.UnpackLog(event, "OrderValidated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SeaportV15OrdersMatchedIterator is returned from FilterOrdersMatched and is used to iterate over the raw logs and unpacked data for OrdersMatched events raised by the SeaportV15 contract.
+type SeaportV15OrdersMatchedIterator struct {
+	Event *SeaportV15OrdersMatched // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportV15OrdersMatchedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportV15OrdersMatched)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportV15OrdersMatched)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportV15OrdersMatchedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportV15OrdersMatchedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportV15OrdersMatched represents a OrdersMatched event raised by the SeaportV15 contract.
+type SeaportV15OrdersMatched struct {
+	OrderHashes [][32]byte
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrdersMatched is a free log retrieval operation binding the contract event 0x4b9f2d36e1b4c93de62cc077b00b1a91d84b6c31b4a14e012718dcca230689e7.
+//
+// Solidity: event OrdersMatched(bytes32[] orderHashes)
+func (_SeaportV15 *SeaportV15Filterer) FilterOrdersMatched(opts *bind.FilterOpts) (*SeaportV15OrdersMatchedIterator, error) {
+
+	logs, sub, err := _SeaportV15.contract.FilterLogs(opts, "OrdersMatched")
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportV15OrdersMatchedIterator{contract: _SeaportV15.contract, event: "OrdersMatched", logs: logs, sub: sub}, nil
+}
+
+// WatchOrdersMatched is a free log subscription operation binding the contract event 0x4b9f2d36e1b4c93de62cc077b00b1a91d84b6c31b4a14e012718dcca230689e7.
+//
+// Solidity: event OrdersMatched(bytes32[] orderHashes)
+func (_SeaportV15 *SeaportV15Filterer) WatchOrdersMatched(opts *bind.WatchOpts, sink chan<- *SeaportV15OrdersMatched) (event.Subscription, error) {
+
+	logs, sub, err := _SeaportV15.contract.WatchLogs(opts, "OrdersMatched")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportV15OrdersMatched)
+				if err := _SeaportV15.contract.UnpackLog(event, "OrdersMatched", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrdersMatched is a log parse operation binding the contract event 0x4b9f2d36e1b4c93de62cc077b00b1a91d84b6c31b4a14e012718dcca230689e7.
+//
+// Solidity: event OrdersMatched(bytes32[] orderHashes)
+func (_SeaportV15 *SeaportV15Filterer) ParseOrdersMatched(log types.Log) (*SeaportV15OrdersMatched, error) {
+	event := new(SeaportV15OrdersMatched)
+	if err := _SeaportV15.contract.UnpackLog(event, "OrdersMatched", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
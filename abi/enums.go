@@ -0,0 +1,30 @@
+package abi
+
+// Seaport ItemType enum
+const (
+	ItemTypeNative uint8 = iota
+	ItemTypeERC20
+	ItemTypeERC721
+	ItemTypeERC1155
+	ItemTypeERC721WithCriteria
+	ItemTypeERC1155WithCriteria
+)
+
+// Seaport OrderType enum
+const (
+	OrderTypeFullOpen uint8 = iota
+	OrderTypePartialOpen
+	OrderTypeFullRestricted
+	OrderTypePartialRestricted
+	OrderTypeContract
+)
+
+// Returns true if the item type is an ERC721 or ERC1155 token
+func IsNFT(itemType uint8) bool {
+	return itemType >= ItemTypeERC721 && itemType <= ItemTypeERC1155WithCriteria
+}
+
+// Returns true if the item type is resolved through a criteria merkle root
+func IsCriteria(itemType uint8) bool {
+	return itemType == ItemTypeERC721WithCriteria || itemType == ItemTypeERC1155WithCriteria
+}
@@ -0,0 +1,59 @@
+package abi
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Seaport versions with a binding in this package
+const (
+	SeaportV1_1 = "1.1"
+	SeaportV1_4 = "1.4"
+	SeaportV1_5 = "1.5"
+	SeaportV1_6 = "1.6"
+)
+
+var ErrUnknownSeaportVersion = errors.New("unknown Seaport version")
+
+// Canonical Seaport deployments by version, deployed at the same address on every chain
+var SeaportAddresses = map[string]common.Address{
+	SeaportV1_1: common.HexToAddress("0x00000000006c3852cbEf3e08E8dF289169EdE581"),
+	SeaportV1_4: common.HexToAddress("0x00000000000001ad428e4906aE43D8F9852d0dD6"),
+	SeaportV1_5: common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"),
+	SeaportV1_6: common.HexToAddress("0x0000000000000068F116a894984e2DB1123eB395"),
+}
+
+// Read-only calls that are the same on every Seaport version
+type SeaportReader interface {
+	GetCounter(opts *bind.CallOpts, offerer common.Address) (*big.Int, error)
+	GetOrderStatus(opts *bind.CallOpts, orderHash [32]byte) (struct {
+		IsValidated bool
+		IsCancelled bool
+		TotalFilled *big.Int
+		TotalSize   *big.Int
+	}, error)
+	Information(opts *bind.CallOpts) (struct {
+		Version           string
+		DomainSeparator   [32]byte
+		ConduitController common.Address
+	}, error)
+}
+
+// Creates a read-only binding to a Seaport deployment of the given version
+func NewSeaportReader(version string, address common.Address, caller bind.ContractCaller) (SeaportReader, error) {
+	switch version {
+	case SeaportV1_1:
+		return NewSeaportCaller(address, caller)
+	case SeaportV1_4:
+		return NewSeaportV14Caller(address, caller)
+	case SeaportV1_5:
+		return NewSeaportV15Caller(address, caller)
+	case SeaportV1_6:
+		return NewSeaportV16Caller(address, caller)
+	}
+
+	return nil, ErrUnknownSeaportVersion
+}
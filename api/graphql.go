@@ -0,0 +1,608 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"goport/db"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Numbers are decimal strings, addresses and hashes 0x prefixed hex strings
+const schema = `
+schema {
+	query: Query
+}
+
+# Chain id, a number or a decimal string. Chain ids do not all fit in Int, which is 32 bits.
+scalar ChainId
+
+type Query {
+	# Order with the given hash, on the node's default chain if chainId is not set
+	order(hash: String!, chainId: ChainId): Order
+	orders(filters: OrderFilters, sort: OrderSort = NEWEST, pagination: Pagination): [Order!]!
+	stats(chainId: ChainId, collection: String): Stats!
+}
+
+enum Side {
+	LISTING
+	OFFER
+}
+
+enum OrderSort {
+	NEWEST
+	OLDEST
+	ENDING_SOON
+	PRICE_LOW_TO_HIGH
+	PRICE_HIGH_TO_LOW
+	RECENTLY_FULFILLED
+	RECENTLY_VALIDATED
+}
+
+enum OrderStatus {
+	ACTIVE
+	INVALID_BALANCE
+	INVALID_APPROVAL
+	CANCELLED
+	FILLED
+	EXPIRED
+	STALE_COUNTER
+	INVALID
+}
+
+enum ItemType {
+	NATIVE
+	ERC20
+	ERC721
+	ERC1155
+	ERC721_WITH_CRITERIA
+	ERC1155_WITH_CRITERIA
+}
+
+enum OrderType {
+	FULL_OPEN
+	PARTIAL_OPEN
+	FULL_RESTRICTED
+	PARTIAL_RESTRICTED
+	CONTRACT
+}
+
+input OrderFilters {
+	chainId: ChainId
+	# Listings and offers if not set
+	side: Side
+	offerer: String
+	collection: String
+	# Orders with an item of the collection with this token id
+	tokenId: String
+	# Orders with an item of this type
+	itemType: ItemType
+	minPrice: String
+	maxPrice: String
+	status: OrderStatus
+}
+
+input Pagination {
+	offset: Int = 0
+	limit: Int = 20
+}
+
+type Order {
+	chainId: ChainId!
+	hash: String!
+	seaport: String!
+	seaportVersion: String!
+	offerer: String!
+	zone: String!
+	offer: [OfferItem!]!
+	consideration: [ConsiderationItem!]!
+	orderType: OrderType!
+	startTime: String!
+	endTime: String!
+	zoneHash: String!
+	salt: String!
+	conduitKey: String!
+	counter: String!
+	totalOriginalConsiderationItems: Int!
+	signature: String!
+
+	status: OrderStatus!
+	isValidated: Boolean!
+	isCancelled: Boolean!
+	isFullyFulfilled: Boolean!
+	totalFilled: String!
+	totalSize: String!
+
+	side: Side!
+	collection: String!
+	price: String!
+	createdAt: String!
+}
+
+type OfferItem {
+	itemType: ItemType!
+	token: String!
+	identifierOrCriteria: String!
+	startAmount: String!
+	endAmount: String!
+}
+
+type ConsiderationItem {
+	itemType: ItemType!
+	token: String!
+	identifierOrCriteria: String!
+	startAmount: String!
+	endAmount: String!
+	recipient: String!
+}
+
+type Stats {
+	# Stored orders in every status
+	orders: Int!
+	# Active listings and offers
+	listings: Int!
+	offers: Int!
+	# Lowest active listing and highest active offer, null if there are none
+	floorPrice: String
+	topOffer: String
+}
+`
+
+// Default and maximum number of results of a query
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+var (
+	sides      = []string{"LISTING", "OFFER"}
+	statuses   = []string{"ACTIVE", "INVALID_BALANCE", "INVALID_APPROVAL", "CANCELLED", "FILLED", "EXPIRED", "STALE_COUNTER", "INVALID"}
+	sorts      = []string{"NEWEST", "OLDEST", "ENDING_SOON", "PRICE_LOW_TO_HIGH", "PRICE_HIGH_TO_LOW", "RECENTLY_FULFILLED", "RECENTLY_VALIDATED"}
+	itemTypes  = []string{"NATIVE", "ERC20", "ERC721", "ERC1155", "ERC721_WITH_CRITERIA", "ERC1155_WITH_CRITERIA"}
+	orderTypes = []string{"FULL_OPEN", "PARTIAL_OPEN", "FULL_RESTRICTED", "PARTIAL_RESTRICTED", "CONTRACT"}
+)
+
+// GraphQL ChainId scalar, written as a number. Ids past 32 bits must be passed as a variable or as a
+// decimal string, GraphQL number literals are 32 bit.
+type ChainID int64
+
+func (ChainID) ImplementsGraphQLType(name string) bool {
+	return name == "ChainId"
+}
+
+func (id *ChainID) UnmarshalGraphQL(input interface{}) error {
+	var v int64
+
+	switch input := input.(type) {
+	case int32:
+		v = int64(input)
+	case float64:
+		v = int64(input)
+		if float64(v) != input {
+			return ErrInvalidNumber
+		}
+	case string:
+		n, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return ErrInvalidNumber
+		}
+		v = n
+	default:
+		return ErrInvalidNumber
+	}
+
+	if v < 0 {
+		return ErrInvalidNumber
+	}
+	*id = ChainID(v)
+
+	return nil
+}
+
+func (id ChainID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+// Root resolver of the GraphQL schema
+type resolver struct {
+	s *Server
+}
+
+type orderFilters struct {
+	ChainID    *ChainID
+	Side       *string
+	Offerer    *string
+	Collection *string
+	TokenID    *string
+	ItemType   *string
+	MinPrice   *string
+	MaxPrice   *string
+	Status     *string
+}
+
+type pagination struct {
+	Offset int32
+	Limit  int32
+}
+
+func (r *resolver) Order(ctx context.Context, args struct {
+	Hash    string
+	ChainID *ChainID
+}) (*orderResolver, error) {
+	hash, err := parseHash(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := r.s.ChainID
+	if args.ChainID != nil {
+		chainID = int64(*args.ChainID)
+	}
+
+	o, err := r.s.Store.GetOrder(ctx, chainID, hash)
+	if errors.Is(err, db.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderResolver{o}, nil
+}
+
+func (r *resolver) Orders(ctx context.Context, args struct {
+	Filters    *orderFilters
+	Sort       string
+	Pagination *pagination
+}) ([]*orderResolver, error) {
+	q, err := orderQuery(args.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := enumIndex(sorts, args.Sort)
+	if err != nil {
+		return nil, err
+	}
+	q.Sort = db.OrderSort(sort)
+
+	q.Limit = defaultLimit
+	if p := args.Pagination; p != nil {
+		if p.Offset > 0 {
+			q.Offset = int(p.Offset)
+		}
+		if p.Limit > 0 {
+			q.Limit = int(p.Limit)
+		}
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+
+	orders, err := r.s.Store.QueryOrders(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*orderResolver, len(orders))
+	for i, o := range orders {
+		res[i] = &orderResolver{o}
+	}
+
+	return res, nil
+}
+
+func (r *resolver) Stats(ctx context.Context, args struct {
+	ChainID    *ChainID
+	Collection *string
+}) (*statsResolver, error) {
+	q := db.OrderQuery{}
+
+	if args.ChainID != nil {
+		q.ChainID = int64(*args.ChainID)
+	}
+
+	if args.Collection != nil {
+		c, err := parseAddress(*args.Collection)
+		if err != nil {
+			return nil, err
+		}
+		q.Collection = c
+	}
+
+	return &statsResolver{s: r.s, q: q}, nil
+}
+
+// Returns the store query of the order filters
+func orderQuery(f *orderFilters) (db.OrderQuery, error) {
+	q := db.OrderQuery{}
+	if f == nil {
+		return q, nil
+	}
+
+	if f.ChainID != nil {
+		q.ChainID = int64(*f.ChainID)
+	}
+
+	var err error
+
+	if f.Side != nil {
+		side, err := enumIndex(sides, *f.Side)
+		if err != nil {
+			return q, err
+		}
+		s := db.OrderSide(side)
+		q.Side = &s
+	}
+
+	if f.Offerer != nil {
+		if q.Offerer, err = parseAddress(*f.Offerer); err != nil {
+			return q, err
+		}
+	}
+	if f.Collection != nil {
+		if q.Collection, err = parseAddress(*f.Collection); err != nil {
+			return q, err
+		}
+	}
+	if f.TokenID != nil {
+		if q.TokenID, err = parseBig(*f.TokenID); err != nil {
+			return q, err
+		}
+	}
+	if f.ItemType != nil {
+		itemType, err := enumIndex(itemTypes, *f.ItemType)
+		if err != nil {
+			return q, err
+		}
+		t := uint8(itemType)
+		q.ItemType = &t
+	}
+	if f.MinPrice != nil {
+		if q.MinPrice, err = parseBig(*f.MinPrice); err != nil {
+			return q, err
+		}
+	}
+	if f.MaxPrice != nil {
+		if q.MaxPrice, err = parseBig(*f.MaxPrice); err != nil {
+			return q, err
+		}
+	}
+	if f.Status != nil {
+		q.Status = fromEnum(*f.Status)
+	}
+
+	return q, nil
+}
+
+type orderResolver struct {
+	o *db.Order
+}
+
+func (r *orderResolver) ChainID() ChainID {
+	return ChainID(r.o.ChainID)
+}
+
+func (r *orderResolver) Hash() string {
+	return r.o.Hash.Hex()
+}
+
+func (r *orderResolver) Seaport() string {
+	return r.o.Seaport.Hex()
+}
+
+func (r *orderResolver) SeaportVersion() string {
+	return r.o.SeaportVersion
+}
+
+func (r *orderResolver) Offerer() string {
+	return r.o.Offerer.Hex()
+}
+
+func (r *orderResolver) Zone() string {
+	return r.o.Zone.Hex()
+}
+
+func (r *orderResolver) Offer() []*offerItemResolver {
+	items := make([]*offerItemResolver, len(r.o.Offer))
+	for i, item := range r.o.Offer {
+		items[i] = &offerItemResolver{item}
+	}
+
+	return items
+}
+
+func (r *orderResolver) Consideration() []*considerationItemResolver {
+	items := make([]*considerationItemResolver, len(r.o.Consideration))
+	for i, item := range r.o.Consideration {
+		items[i] = &considerationItemResolver{item}
+	}
+
+	return items
+}
+
+func (r *orderResolver) OrderType() string {
+	return enumName(orderTypes, r.o.OrderType)
+}
+
+func (r *orderResolver) StartTime() string {
+	return r.o.StartTime.String()
+}
+
+func (r *orderResolver) EndTime() string {
+	return r.o.EndTime.String()
+}
+
+func (r *orderResolver) ZoneHash() string {
+	return r.o.ZoneHash.Hex()
+}
+
+func (r *orderResolver) Salt() string {
+	return r.o.Salt.String()
+}
+
+func (r *orderResolver) ConduitKey() string {
+	return r.o.ConduitKey.Hex()
+}
+
+func (r *orderResolver) Counter() string {
+	return r.o.Counter.String()
+}
+
+func (r *orderResolver) TotalOriginalConsiderationItems() int32 {
+	return int32(r.o.TotalOriginalConsiderationItems)
+}
+
+func (r *orderResolver) Signature() string {
+	return hexutil.Encode(r.o.Signature)
+}
+
+func (r *orderResolver) Status() string {
+	return toEnum(r.o.Status)
+}
+
+func (r *orderResolver) IsValidated() bool {
+	return r.o.IsValidated
+}
+
+func (r *orderResolver) IsCancelled() bool {
+	return r.o.Status == db.StatusCancelled
+}
+
+func (r *orderResolver) IsFullyFulfilled() bool {
+	return r.o.Status == db.StatusFilled
+}
+
+func (r *orderResolver) TotalFilled() string {
+	return r.o.TotalFilled.String()
+}
+
+func (r *orderResolver) TotalSize() string {
+	return r.o.TotalSize.String()
+}
+
+func (r *orderResolver) Side() string {
+	return enumName(sides, uint8(r.o.Side))
+}
+
+func (r *orderResolver) Collection() string {
+	return r.o.Collection.Hex()
+}
+
+func (r *orderResolver) Price() string {
+	return r.o.Price.String()
+}
+
+func (r *orderResolver) CreatedAt() string {
+	return r.o.CreatedAt.UTC().Format(timeFormat)
+}
+
+type offerItemResolver struct {
+	item *db.OfferItem
+}
+
+func (r *offerItemResolver) ItemType() string {
+	return enumName(itemTypes, r.item.ItemType)
+}
+
+func (r *offerItemResolver) Token() string {
+	return r.item.Token.Hex()
+}
+
+func (r *offerItemResolver) IdentifierOrCriteria() string {
+	return r.item.IdentifierOrCriteria.String()
+}
+
+func (r *offerItemResolver) StartAmount() string {
+	return r.item.StartAmount.String()
+}
+
+func (r *offerItemResolver) EndAmount() string {
+	return r.item.EndAmount.String()
+}
+
+type considerationItemResolver struct {
+	item *db.ConsiderationItem
+}
+
+func (r *considerationItemResolver) ItemType() string {
+	return enumName(itemTypes, r.item.ItemType)
+}
+
+func (r *considerationItemResolver) Token() string {
+	return r.item.Token.Hex()
+}
+
+func (r *considerationItemResolver) IdentifierOrCriteria() string {
+	return r.item.IdentifierOrCriteria.String()
+}
+
+func (r *considerationItemResolver) StartAmount() string {
+	return r.item.StartAmount.String()
+}
+
+func (r *considerationItemResolver) EndAmount() string {
+	return r.item.EndAmount.String()
+}
+
+func (r *considerationItemResolver) Recipient() string {
+	return r.item.Recipient.Hex()
+}
+
+// Resolves the statistics of the orders matching a query, each field runs its own count
+type statsResolver struct {
+	s *Server
+	q db.OrderQuery
+}
+
+func (r *statsResolver) Orders(ctx context.Context) (int32, error) {
+	n, err := r.s.Store.CountOrders(ctx, r.q)
+
+	return int32(n), err
+}
+
+func (r *statsResolver) Listings(ctx context.Context) (int32, error) {
+	return r.count(ctx, db.SellSide)
+}
+
+func (r *statsResolver) Offers(ctx context.Context) (int32, error) {
+	return r.count(ctx, db.BuySide)
+}
+
+func (r *statsResolver) FloorPrice(ctx context.Context) (*string, error) {
+	return r.best(ctx, db.SellSide, db.SortPriceLowToHigh)
+}
+
+func (r *statsResolver) TopOffer(ctx context.Context) (*string, error) {
+	return r.best(ctx, db.BuySide, db.SortPriceHighToLow)
+}
+
+// Counts the active orders of a side
+func (r *statsResolver) count(ctx context.Context, side db.OrderSide) (int32, error) {
+	q := r.q
+	q.Side = &side
+	q.Status = db.StatusActive
+
+	n, err := r.s.Store.CountOrders(ctx, q)
+
+	return int32(n), err
+}
+
+// Returns the price of the first active order of a side in the sort order
+func (r *statsResolver) best(ctx context.Context, side db.OrderSide, sort db.OrderSort) (*string, error) {
+	q := r.q
+	q.Side = &side
+	q.Status = db.StatusActive
+	q.Sort = sort
+	q.Limit = 1
+
+	orders, err := r.s.Store.QueryOrders(ctx, q)
+	if err != nil || len(orders) == 0 {
+		return nil, err
+	}
+
+	price := orders[0].Price.String()
+
+	return &price, nil
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Runs a GraphQL query, failing the test on errors
+func graphqlQuery(t *testing.T, s *Server, query string, variables map[string]interface{}, res interface{}) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+	var out struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%s: %v", rec.Body, err)
+	}
+	if len(out.Errors) > 0 {
+		t.Fatalf("query %s failed: %v", query, out.Errors)
+	}
+
+	if err := json.Unmarshal(out.Data, res); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphQLOrdersSide(t *testing.T) {
+	s, err := NewServer(sidesStore(t), 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	cases := []struct {
+		filters string
+		want    int
+	}{
+		{"", 2},
+		{"(filters: {chainId: 1})", 2},
+		{"(filters: {side: LISTING})", 1},
+		{"(filters: {side: OFFER})", 1},
+	}
+
+	for _, tc := range cases {
+		var res struct {
+			Orders []struct{ Side string }
+		}
+		graphqlQuery(t, s, "{ orders"+tc.filters+" { side } }", nil, &res)
+
+		if len(res.Orders) != tc.want {
+			t.Fatalf("orders%s returned %d orders, want %d", tc.filters, len(res.Orders), tc.want)
+		}
+	}
+}
+
+func TestGraphQLChainID(t *testing.T) {
+	s, err := NewServer(sidesStore(t), 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	var res struct {
+		Orders []struct{ ChainID int64 }
+	}
+	graphqlQuery(t, s, `{ orders(filters: {chainId: "1"}) { chainId } }`, nil, &res)
+	if len(res.Orders) != 2 || res.Orders[0].ChainID != 1 {
+		t.Fatalf("orders of chain \"1\" = %+v, want 2 orders of chain 1", res.Orders)
+	}
+
+	// Chain ids past 32 bits are passed in variables
+	graphqlQuery(t, s, `query($chainId: ChainId) { orders(filters: {chainId: $chainId}) { chainId } }`, map[string]interface{}{"chainId": 1 << 40}, &res)
+	if len(res.Orders) != 0 {
+		t.Fatalf("orders of chain %d = %+v, want none", 1<<40, res.Orders)
+	}
+
+	graphqlQuery(t, s, `{ orders(filters: {chainId: "1099511627776"}) { chainId } }`, nil, &res)
+	if len(res.Orders) != 0 {
+		t.Fatalf("orders of chain %d = %+v, want none", 1<<40, res.Orders)
+	}
+
+	var id ChainID
+	for _, input := range []interface{}{"x", -1.0, 1.5, true} {
+		if err := id.UnmarshalGraphQL(input); err == nil {
+			t.Fatalf("chain id %v was accepted", input)
+		}
+	}
+}
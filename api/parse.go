@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrInvalidHash    = errors.New("invalid hash")
+	ErrInvalidNumber  = errors.New("invalid number")
+	ErrInvalidEnum    = errors.New("invalid enum value")
+)
+
+// Returns the position of a value in an enum
+func enumIndex(values []string, v string) (int, error) {
+	for i, value := range values {
+		if value == v {
+			return i, nil
+		}
+	}
+
+	return 0, ErrInvalidEnum
+}
+
+// Returns the enum value at a position, or an empty string if there is none
+func enumName(values []string, i uint8) string {
+	if int(i) >= len(values) {
+		return ""
+	}
+
+	return values[i]
+}
+
+// Statuses are stored and passed to the REST API in kebab case, e.g. invalid-balance is INVALID_BALANCE
+func toEnum(v string) string {
+	return strings.ToUpper(strings.ReplaceAll(v, "-", "_"))
+}
+
+func fromEnum(v string) string {
+	return strings.ToLower(strings.ReplaceAll(v, "_", "-"))
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, ErrInvalidAddress
+	}
+
+	return common.HexToAddress(s), nil
+}
+
+func parseHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, ErrInvalidHash
+	}
+
+	return common.BytesToHash(b), nil
+}
+
+// Parses a decimal or 0x prefixed hex uint256
+func parseBig(s string) (*big.Int, error) {
+	x, ok := math.ParseBig256(s)
+	if !ok || x.Sign() < 0 {
+		return nil, ErrInvalidNumber
+	}
+
+	return x, nil
+}
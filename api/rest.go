@@ -0,0 +1,781 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"goport/abi"
+	"goport/db"
+	"goport/order"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Largest request body accepted, an order posted to POST /orders is far below it
+const maxBodySize = 1 << 20
+
+var (
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidBody        = errors.New("invalid request body")
+	ErrNotFound           = errors.New("not found")
+	ErrSubmissionDisabled = errors.New("order submission is disabled")
+)
+
+// Order as returned by the REST API, numbers are decimal strings
+type orderJSON struct {
+	ChainID                         int64                    `json:"chainId"`
+	Hash                            common.Hash              `json:"hash"`
+	Seaport                         common.Address           `json:"seaport"`
+	SeaportVersion                  string                   `json:"seaportVersion"`
+	Offerer                         common.Address           `json:"offerer"`
+	Zone                            common.Address           `json:"zone"`
+	Offer                           []*offerItemJSON         `json:"offer"`
+	Consideration                   []*considerationItemJSON `json:"consideration"`
+	OrderType                       uint8                    `json:"orderType"`
+	StartTime                       *db.Uint256              `json:"startTime"`
+	EndTime                         *db.Uint256              `json:"endTime"`
+	ZoneHash                        common.Hash              `json:"zoneHash"`
+	Salt                            *db.Uint256              `json:"salt"`
+	ConduitKey                      common.Hash              `json:"conduitKey"`
+	Counter                         *db.Uint256              `json:"counter"`
+	TotalOriginalConsiderationItems int                      `json:"totalOriginalConsiderationItems"`
+	Signature                       hexutil.Bytes            `json:"signature"`
+
+	Status      string      `json:"status"`
+	IsValidated bool        `json:"isValidated"`
+	TotalFilled *db.Uint256 `json:"totalFilled"`
+	TotalSize   *db.Uint256 `json:"totalSize"`
+	ValidatedAt *time.Time  `json:"validatedAt,omitempty"`
+	FilledAt    *time.Time  `json:"filledAt,omitempty"`
+
+	Side       string         `json:"side"`
+	Collection common.Address `json:"collection"`
+	Price      *db.Uint256    `json:"price"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type offerItemJSON struct {
+	ItemType             uint8          `json:"itemType"`
+	Token                common.Address `json:"token"`
+	IdentifierOrCriteria *db.Uint256    `json:"identifierOrCriteria"`
+	StartAmount          *db.Uint256    `json:"startAmount"`
+	EndAmount            *db.Uint256    `json:"endAmount"`
+}
+
+type considerationItemJSON struct {
+	ItemType             uint8          `json:"itemType"`
+	Token                common.Address `json:"token"`
+	IdentifierOrCriteria *db.Uint256    `json:"identifierOrCriteria"`
+	StartAmount          *db.Uint256    `json:"startAmount"`
+	EndAmount            *db.Uint256    `json:"endAmount"`
+	Recipient            common.Address `json:"recipient"`
+}
+
+// Seaport event as returned by the REST API, the fields that do not apply to its type are left out
+type eventJSON struct {
+	Type           string         `json:"type"`
+	ChainID        int64          `json:"chainId"`
+	Seaport        common.Address `json:"seaport"`
+	SeaportVersion string         `json:"seaportVersion"`
+	BlockNumber    uint64         `json:"blockNumber"`
+	BlockHash      common.Hash    `json:"blockHash"`
+	BlockTimestamp *time.Time     `json:"blockTimestamp,omitempty"`
+	TxHash         common.Hash    `json:"txHash"`
+	TxSender       common.Address `json:"txSender"`
+	LogIndex       uint           `json:"logIndex"`
+
+	OrderHash     *common.Hash       `json:"orderHash,omitempty"`
+	Offerer       common.Address     `json:"offerer"`
+	Zone          *common.Address    `json:"zone,omitempty"`
+	Recipient     *common.Address    `json:"recipient,omitempty"`
+	Offer         []*db.SpentItem    `json:"offer,omitempty"`
+	Consideration []*db.ReceivedItem `json:"consideration,omitempty"`
+	Counter       *db.Uint256        `json:"counter,omitempty"`
+}
+
+// Signed order posted to the API, in the format of seaport-js
+type submittedOrderJSON struct {
+	// Defaults to the first chain
+	ChainID    int64               `json:"chainId"`
+	Parameters orderComponentsJSON `json:"parameters"`
+	Signature  hexutil.Bytes       `json:"signature"`
+
+	// Token ids of the criteria items of the order, one list per merkle root of its items
+	Criteria [][]*db.Uint256 `json:"criteria,omitempty"`
+}
+
+type orderComponentsJSON struct {
+	Offerer       common.Address           `json:"offerer"`
+	Zone          common.Address           `json:"zone"`
+	Offer         []*offerItemJSON         `json:"offer"`
+	Consideration []*considerationItemJSON `json:"consideration"`
+	OrderType     uint8                    `json:"orderType"`
+	StartTime     *db.Uint256              `json:"startTime"`
+	EndTime       *db.Uint256              `json:"endTime"`
+	ZoneHash      common.Hash              `json:"zoneHash"`
+	Salt          *db.Uint256              `json:"salt"`
+	ConduitKey    common.Hash              `json:"conduitKey"`
+	Counter       *db.Uint256              `json:"counter"`
+}
+
+// Page of results, next is the cursor of the following page and is left out on the last page
+type ordersPage struct {
+	Orders []*orderJSON `json:"orders"`
+	Next   string       `json:"next,omitempty"`
+}
+
+type eventsPage struct {
+	Events []*eventJSON `json:"events"`
+	Next   string       `json:"next,omitempty"`
+}
+
+func newOrderJSON(o *db.Order) *orderJSON {
+	j := &orderJSON{
+		ChainID:                         o.ChainID,
+		Hash:                            o.Hash,
+		Seaport:                         o.Seaport,
+		SeaportVersion:                  o.SeaportVersion,
+		Offerer:                         o.Offerer,
+		Zone:                            o.Zone,
+		Offer:                           make([]*offerItemJSON, len(o.Offer)),
+		Consideration:                   make([]*considerationItemJSON, len(o.Consideration)),
+		OrderType:                       o.OrderType,
+		StartTime:                       o.StartTime,
+		EndTime:                         o.EndTime,
+		ZoneHash:                        o.ZoneHash,
+		Salt:                            o.Salt,
+		ConduitKey:                      o.ConduitKey,
+		Counter:                         o.Counter,
+		TotalOriginalConsiderationItems: o.TotalOriginalConsiderationItems,
+		Signature:                       o.Signature,
+		Status:                          o.Status,
+		IsValidated:                     o.IsValidated,
+		TotalFilled:                     o.TotalFilled,
+		TotalSize:                       o.TotalSize,
+		Side:                            fromEnum(enumName(sides, uint8(o.Side))),
+		Collection:                      o.Collection,
+		Price:                           o.Price,
+		CreatedAt:                       o.CreatedAt.UTC(),
+	}
+
+	if !o.ValidatedAt.IsZero() {
+		t := o.ValidatedAt.UTC()
+		j.ValidatedAt = &t
+	}
+
+	if !o.FilledAt.IsZero() {
+		t := o.FilledAt.UTC()
+		j.FilledAt = &t
+	}
+
+	for i, item := range o.Offer {
+		j.Offer[i] = &offerItemJSON{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria,
+			StartAmount:          item.StartAmount,
+			EndAmount:            item.EndAmount,
+		}
+	}
+
+	for i, item := range o.Consideration {
+		j.Consideration[i] = &considerationItemJSON{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria,
+			StartAmount:          item.StartAmount,
+			EndAmount:            item.EndAmount,
+			Recipient:            item.Recipient,
+		}
+	}
+
+	return j
+}
+
+func newEventJSON(e *db.EventRecord) *eventJSON {
+	j := &eventJSON{
+		Type:           e.Type,
+		ChainID:        e.ChainID,
+		Seaport:        e.Seaport,
+		SeaportVersion: e.SeaportVersion,
+		BlockNumber:    e.BlockNumber,
+		BlockHash:      e.BlockHash,
+		TxHash:         e.TxHash,
+		TxSender:       e.TxSender,
+		LogIndex:       e.LogIndex,
+		Offerer:        e.Offerer,
+		Offer:          e.Offer,
+		Consideration:  e.Consideration,
+		Counter:        e.Counter,
+	}
+
+	if !e.BlockTimestamp.IsZero() {
+		t := e.BlockTimestamp.UTC()
+		j.BlockTimestamp = &t
+	}
+
+	if e.Type != db.EventCounterIncremented {
+		hash, zone := e.OrderHash, e.Zone
+		j.OrderHash = &hash
+		j.Zone = &zone
+	}
+
+	if e.Type == db.EventOrderFulfilled {
+		recipient := e.Recipient
+		j.Recipient = &recipient
+	}
+
+	return j
+}
+
+// Routes the REST API:
+//
+//	GET /orders
+//	POST /orders
+//	GET /orders/{hash}
+//	GET /collections/{address}/listings
+//	GET /collections/{address}/offers
+//	GET /collections/{address}/events
+//	GET /events
+func (s *Server) handleREST(mux *http.ServeMux) {
+	mux.HandleFunc("/orders", s.route(map[string]handler{
+		http.MethodGet:  s.getOrders,
+		http.MethodPost: s.postOrder,
+	}))
+	mux.HandleFunc("/orders/", s.get(s.getOrder))
+	mux.HandleFunc("/collections/", s.get(s.getCollection))
+	mux.HandleFunc("/events", s.get(s.getEvents))
+}
+
+type handler func(r *http.Request) (interface{}, error)
+
+// Wraps the handlers of a path by request method, errors are returned as {"error": "..."}
+func (s *Server) route(handlers map[string]handler) http.HandlerFunc {
+	allow := make([]string, 0, len(handlers))
+	for m := range handlers {
+		allow = append(allow, m)
+	}
+	sort.Strings(allow)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, ok := handlers[r.Method]
+		if !ok {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		// Reading past the limit fails and closes the connection once the response is written
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+		res, err := h(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+
+		writeJSON(w, status, res)
+	}
+}
+
+// Wraps a handler that only answers GET requests
+func (s *Server) get(h handler) http.HandlerFunc {
+	return s.route(map[string]handler{http.MethodGet: h})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write API response: %v", err.Error())
+	}
+}
+
+// Invalid parameters are client errors, anything else is logged and hidden from the client
+func writeError(w http.ResponseWriter, err error) {
+	var verr *order.ValidationError
+
+	switch {
+	case errors.As(err, &verr):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"error": "order failed validation", "codes": verr.Result.Errors})
+	case errors.Is(err, ErrSubmissionDisabled), errors.Is(err, ErrStreamingDisabled):
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAddress), errors.Is(err, ErrInvalidHash), errors.Is(err, ErrInvalidNumber),
+		errors.Is(err, ErrInvalidEnum), errors.Is(err, ErrInvalidCursor), errors.Is(err, ErrInvalidBody),
+		errors.Is(err, order.ErrNoDeployment), errors.Is(err, db.ErrCriteriaMismatch):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("API request failed: %v", err.Error())
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+	}
+}
+
+func (s *Server) getOrders(r *http.Request) (interface{}, error) {
+	q, err := restOrderQuery(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ordersPage(r, q)
+}
+
+// Submits a signed order, it is returned as stored once it passed validation
+func (s *Server) postOrder(r *http.Request) (interface{}, error) {
+	if s.Submitter == nil {
+		return nil, ErrSubmissionDisabled
+	}
+
+	var body submittedOrderJSON
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, ErrInvalidBody
+	}
+
+	c, err := body.Parameters.components()
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := body.ChainID
+	if chainID == 0 {
+		chainID = s.ChainID
+	}
+
+	criteria, err := submittedCriteria(c, body.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := s.Submitter.SubmitOrder(r.Context(), chainID, c, body.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// The criteria are only stored for orders that passed validation
+	for _, tokenIDs := range criteria {
+		if _, err := s.Submitter.AddCriteria(r.Context(), tokenIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return newOrderJSON(o), nil
+}
+
+// Returns the token id lists of a submitted order, each must hash to the root of one of its criteria
+// items
+func submittedCriteria(c *abi.OrderComponents, lists [][]*db.Uint256) ([][]*big.Int, error) {
+	roots := make(map[common.Hash]bool)
+	for _, item := range c.Offer {
+		if abi.IsCriteria(item.ItemType) {
+			roots[common.BigToHash(item.IdentifierOrCriteria)] = true
+		}
+	}
+	for _, item := range c.Consideration {
+		if abi.IsCriteria(item.ItemType) {
+			roots[common.BigToHash(item.IdentifierOrCriteria)] = true
+		}
+	}
+
+	criteria := make([][]*big.Int, len(lists))
+	for i, ids := range lists {
+		tokenIDs := make([]*big.Int, len(ids))
+		for j, id := range ids {
+			if id == nil {
+				return nil, ErrInvalidNumber
+			}
+			tokenIDs[j] = id.Int()
+		}
+
+		if !roots[order.CriteriaRoot(tokenIDs)] {
+			return nil, db.ErrCriteriaMismatch
+		}
+
+		criteria[i] = tokenIDs
+	}
+
+	return criteria, nil
+}
+
+// Returns the order components, every number must be set
+func (j *orderComponentsJSON) components() (*abi.OrderComponents, error) {
+	if j.StartTime == nil || j.EndTime == nil || j.Salt == nil || j.Counter == nil {
+		return nil, ErrInvalidNumber
+	}
+
+	c := &abi.OrderComponents{
+		Offerer:       j.Offerer,
+		Zone:          j.Zone,
+		Offer:         make([]abi.OfferItem, len(j.Offer)),
+		Consideration: make([]abi.ConsiderationItem, len(j.Consideration)),
+		OrderType:     j.OrderType,
+		StartTime:     j.StartTime.Int(),
+		EndTime:       j.EndTime.Int(),
+		ZoneHash:      j.ZoneHash,
+		Salt:          j.Salt.Int(),
+		ConduitKey:    j.ConduitKey,
+		Counter:       j.Counter.Int(),
+	}
+
+	for i, item := range j.Offer {
+		if item == nil || item.IdentifierOrCriteria == nil || item.StartAmount == nil || item.EndAmount == nil {
+			return nil, ErrInvalidNumber
+		}
+
+		c.Offer[i] = abi.OfferItem{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria.Int(),
+			StartAmount:          item.StartAmount.Int(),
+			EndAmount:            item.EndAmount.Int(),
+		}
+	}
+
+	for i, item := range j.Consideration {
+		if item == nil || item.IdentifierOrCriteria == nil || item.StartAmount == nil || item.EndAmount == nil {
+			return nil, ErrInvalidNumber
+		}
+
+		c.Consideration[i] = abi.ConsiderationItem{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria.Int(),
+			StartAmount:          item.StartAmount.Int(),
+			EndAmount:            item.EndAmount.Int(),
+			Recipient:            item.Recipient,
+		}
+	}
+
+	return c, nil
+}
+
+func (s *Server) getOrder(r *http.Request) (interface{}, error) {
+	hash, err := parseHash(strings.TrimPrefix(r.URL.Path, "/orders/"))
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := s.ChainID
+	if v := r.URL.Query().Get("chainId"); v != "" {
+		if chainID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, ErrInvalidNumber
+		}
+	}
+
+	o, err := s.Store.GetOrder(r.Context(), chainID, hash)
+	if errors.Is(err, db.ErrOrderNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrderJSON(o), nil
+}
+
+// Serves the listings, offers and events of a collection
+func (s *Server) getCollection(r *http.Request) (interface{}, error) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/collections/"), "/")
+	if len(parts) != 2 {
+		return nil, ErrNotFound
+	}
+
+	collection, err := parseAddress(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+
+	switch parts[1] {
+	case "listings", "offers":
+		if parts[1] == "listings" {
+			params.Set("side", "listing")
+		} else {
+			params.Set("side", "offer")
+		}
+		params.Set("collection", collection.Hex())
+
+		q, err := restOrderQuery(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.ordersPage(r, q)
+
+	case "events":
+		params.Set("collection", collection.Hex())
+
+		q, err := restEventQuery(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.eventsPage(r, q)
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *Server) getEvents(r *http.Request) (interface{}, error) {
+	q, err := restEventQuery(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.eventsPage(r, q)
+}
+
+// Returns a page of orders, one more order than the limit is requested to know if there is a next page
+func (s *Server) ordersPage(r *http.Request, q db.OrderQuery) (*ordersPage, error) {
+	limit := q.Limit
+	q.Limit++
+
+	orders, err := s.Store.QueryOrders(r.Context(), q)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ordersPage{Orders: []*orderJSON{}}
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+
+		last := orders[len(orders)-1]
+		page.Next = encodeCursor(&db.OrderCursor{ChainID: last.ChainID, Hash: last.Hash})
+	}
+
+	for _, o := range orders {
+		page.Orders = append(page.Orders, newOrderJSON(o))
+	}
+
+	return page, nil
+}
+
+func (s *Server) eventsPage(r *http.Request, q db.EventQuery) (*eventsPage, error) {
+	limit := q.Limit
+	q.Limit++
+
+	events, err := s.Store.QueryEvents(r.Context(), q)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &eventsPage{Events: []*eventJSON{}}
+
+	if len(events) > limit {
+		events = events[:limit]
+
+		last := events[len(events)-1]
+		page.Next = encodeCursor(&db.EventCursor{ChainID: last.ChainID, BlockNumber: last.BlockNumber, LogIndex: last.LogIndex})
+	}
+
+	for _, e := range events {
+		page.Events = append(page.Events, newEventJSON(e))
+	}
+
+	return page, nil
+}
+
+// Returns the order query of the REST parameters, enums are passed in kebab case, e.g. sort=price-low-to-high
+func restOrderQuery(params url.Values) (db.OrderQuery, error) {
+	q := db.OrderQuery{}
+
+	var err error
+
+	if q.ChainID, err = intParam(params, "chainId"); err != nil {
+		return q, err
+	}
+
+	// Listings and offers unless a side is given
+	if v := params.Get("side"); v != "" {
+		side, err := enumIndex(sides, toEnum(v))
+		if err != nil {
+			return q, err
+		}
+		s := db.OrderSide(side)
+		q.Side = &s
+	}
+
+	if v := params.Get("sort"); v != "" {
+		sort, err := enumIndex(sorts, toEnum(v))
+		if err != nil {
+			return q, err
+		}
+		q.Sort = db.OrderSort(sort)
+	}
+
+	if v := params.Get("collection"); v != "" {
+		if q.Collection, err = parseAddress(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("offerer"); v != "" {
+		if q.Offerer, err = parseAddress(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("status"); v != "" {
+		if _, err := enumIndex(statuses, toEnum(v)); err != nil {
+			return q, err
+		}
+		q.Status = fromEnum(toEnum(v))
+	}
+
+	if v := params.Get("tokenId"); v != "" {
+		if q.TokenID, err = parseBig(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("itemType"); v != "" {
+		itemType, err := enumIndex(itemTypes, toEnum(v))
+		if err != nil {
+			return q, err
+		}
+		t := uint8(itemType)
+		q.ItemType = &t
+	}
+
+	if v := params.Get("minPrice"); v != "" {
+		if q.MinPrice, err = parseBig(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("maxPrice"); v != "" {
+		if q.MaxPrice, err = parseBig(v); err != nil {
+			return q, err
+		}
+	}
+
+	if q.Limit, err = limitParam(params); err != nil {
+		return q, err
+	}
+
+	if v := params.Get("cursor"); v != "" {
+		q.After = new(db.OrderCursor)
+		if err := decodeCursor(v, q.After); err != nil {
+			return q, err
+		}
+	}
+
+	return q, nil
+}
+
+// Returns the event query of the REST parameters, the event type is passed as its name, e.g. type=OrderFulfilled
+func restEventQuery(params url.Values) (db.EventQuery, error) {
+	q := db.EventQuery{}
+
+	var err error
+
+	if q.ChainID, err = intParam(params, "chainId"); err != nil {
+		return q, err
+	}
+
+	if v := params.Get("collection"); v != "" {
+		if q.Collection, err = parseAddress(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("offerer"); v != "" {
+		if q.Offerer, err = parseAddress(v); err != nil {
+			return q, err
+		}
+	}
+
+	if v := params.Get("orderHash"); v != "" {
+		if q.OrderHash, err = parseHash(v); err != nil {
+			return q, err
+		}
+	}
+
+	switch v := params.Get("type"); v {
+	case "", db.EventOrderFulfilled, db.EventOrderCancelled, db.EventOrderValidated, db.EventCounterIncremented:
+		q.Type = v
+	default:
+		return q, ErrInvalidEnum
+	}
+
+	if q.Limit, err = limitParam(params); err != nil {
+		return q, err
+	}
+
+	if v := params.Get("cursor"); v != "" {
+		q.After = new(db.EventCursor)
+		if err := decodeCursor(v, q.After); err != nil {
+			return q, err
+		}
+	}
+
+	return q, nil
+}
+
+func intParam(params url.Values, key string) (int64, error) {
+	v := params.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidNumber
+	}
+
+	return n, nil
+}
+
+// Returns the page size, defaultLimit if it is not set and at most maxLimit
+func limitParam(params url.Values) (int, error) {
+	n, err := intParam(params, "limit")
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case n == 0:
+		return defaultLimit, nil
+	case n > maxLimit:
+		return maxLimit, nil
+	}
+
+	return int(n), nil
+}
+
+// Cursors are opaque to clients, they are the position of the last result as base64 encoded JSON
+func encodeCursor(v interface{}) string {
+	b, _ := json.Marshal(v)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
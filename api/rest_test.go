@@ -0,0 +1,256 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"goport/abi"
+	"goport/db"
+	"goport/order"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRestOrderQueryStatus(t *testing.T) {
+	for _, v := range []string{"stale-counter", "STALE_COUNTER", "Stale-Counter"} {
+		q, err := restOrderQuery(url.Values{"status": {v}})
+		if err != nil {
+			t.Fatalf("status %q: %v", v, err)
+		}
+
+		if q.Status != db.StatusStaleCounter {
+			t.Fatalf("status %q queried as %q, want %q", v, q.Status, db.StatusStaleCounter)
+		}
+	}
+
+	if _, err := restOrderQuery(url.Values{"status": {"stale"}}); err == nil {
+		t.Fatal("unknown status was accepted")
+	}
+}
+
+// Submitter failing the test if an order gets through
+type testSubmitter struct {
+	t *testing.T
+}
+
+func (s testSubmitter) SubmitOrder(ctx context.Context, chainID int64, c *abi.OrderComponents, signature []byte) (*db.Order, error) {
+	s.t.Fatal("order was submitted")
+	return nil, nil
+}
+
+func (s testSubmitter) AddCriteria(ctx context.Context, tokenIDs []*big.Int) (common.Hash, error) {
+	s.t.Fatal("criteria were added")
+	return common.Hash{}, nil
+}
+
+func TestPostOrderBodyLimit(t *testing.T) {
+	s, err := NewServer(db.NewMemoryStore(), 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	s.Submitter = testSubmitter{t}
+
+	body := `{"chainId": 1, "signature": "0x` + strings.Repeat("00", maxBodySize) + `"}`
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST of a %d byte body = %d, want %d", len(body), rec.Code, http.StatusBadRequest)
+	}
+}
+
+// Submitter accepting orders unless err is set, recording the criteria added after them
+type recordingSubmitter struct {
+	err       error
+	submitted int
+	criteria  [][]*big.Int
+}
+
+func (s *recordingSubmitter) SubmitOrder(ctx context.Context, chainID int64, c *abi.OrderComponents, signature []byte) (*db.Order, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.submitted++
+
+	return db.NewOrderFromComponents(order.NewDomain(big.NewInt(chainID), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5]), order.Hash(c), *c, signature), nil
+}
+
+func (s *recordingSubmitter) AddCriteria(ctx context.Context, tokenIDs []*big.Int) (common.Hash, error) {
+	s.criteria = append(s.criteria, tokenIDs)
+
+	return order.CriteriaRoot(tokenIDs), nil
+}
+
+// Returns the body of a listing of any of the token ids, with the token id lists given as criteria
+func criteriaOrderBody(t *testing.T, tokenIDs []*big.Int, criteria ...[]*big.Int) string {
+	t.Helper()
+
+	n := func(x int64) *db.Uint256 { return db.NewUint256(big.NewInt(x)) }
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+
+	body := submittedOrderJSON{
+		ChainID: 1,
+		Parameters: orderComponentsJSON{
+			Offerer: offerer,
+			Offer: []*offerItemJSON{
+				{ItemType: abi.ItemTypeERC721WithCriteria, Token: common.HexToAddress("0x0000000000000000000000000000000000000a11"), IdentifierOrCriteria: db.NewUint256(order.CriteriaRoot(tokenIDs).Big()), StartAmount: n(1), EndAmount: n(1)},
+			},
+			Consideration: []*considerationItemJSON{
+				{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: n(0), StartAmount: n(100), EndAmount: n(100), Recipient: offerer},
+			},
+			StartTime: n(0),
+			EndTime:   n(1 << 40),
+			Salt:      n(1),
+			Counter:   n(0),
+		},
+		Signature: make([]byte, 65),
+	}
+
+	for _, ids := range criteria {
+		list := make([]*db.Uint256, len(ids))
+		for i, id := range ids {
+			list[i] = db.NewUint256(id)
+		}
+		body.Criteria = append(body.Criteria, list)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func TestPostOrderCriteria(t *testing.T) {
+	tokenIDs := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	other := []*big.Int{big.NewInt(4)}
+
+	post := func(sub *recordingSubmitter, body string) int {
+		s, err := NewServer(db.NewMemoryStore(), 1)
+		if err != nil {
+			t.Fatalf("NewServer: %v", err)
+		}
+		s.Submitter = sub
+
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body)))
+
+		return rec.Code
+	}
+
+	// The criteria of the order are stored once it was accepted
+	sub := &recordingSubmitter{}
+	if code := post(sub, criteriaOrderBody(t, tokenIDs, tokenIDs)); code != http.StatusCreated {
+		t.Fatalf("POST of an order with its criteria = %d, want %d", code, http.StatusCreated)
+	}
+	if sub.submitted != 1 || len(sub.criteria) != 1 || len(sub.criteria[0]) != len(tokenIDs) {
+		t.Fatalf("submitted %d orders and added criteria %v, want the order and its token ids", sub.submitted, sub.criteria)
+	}
+
+	// Nothing is stored for an order that fails validation
+	sub = &recordingSubmitter{err: &order.ValidationError{Result: &order.ValidationResult{Errors: []order.ErrorCode{order.CodeInvalidSignature}}}}
+	if code := post(sub, criteriaOrderBody(t, tokenIDs, tokenIDs)); code == http.StatusCreated {
+		t.Fatalf("POST of an invalid order = %d, want an error", code)
+	}
+	if len(sub.criteria) != 0 {
+		t.Fatalf("added criteria %v of an invalid order", sub.criteria)
+	}
+
+	// Token ids of a root the order does not reference are refused before the order is submitted
+	sub = &recordingSubmitter{}
+	if code := post(sub, criteriaOrderBody(t, tokenIDs, tokenIDs, other)); code != http.StatusBadRequest {
+		t.Fatalf("POST of unreferenced criteria = %d, want %d", code, http.StatusBadRequest)
+	}
+	if sub.submitted != 0 || len(sub.criteria) != 0 {
+		t.Fatalf("submitted %d orders and added criteria %v with unreferenced criteria", sub.submitted, sub.criteria)
+	}
+}
+
+// Stores a listing and an offer of the same token, returns the store
+func sidesStore(t *testing.T) *db.MemoryStore {
+	t.Helper()
+
+	n := big.NewInt
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	collection := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	d := order.NewDomain(n(1), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+
+	listing := abi.OrderComponents{
+		Offerer:       offerer,
+		Offer:         []abi.OfferItem{{ItemType: abi.ItemTypeERC721, Token: collection, IdentifierOrCriteria: n(1), StartAmount: n(1), EndAmount: n(1)}},
+		Consideration: []abi.ConsiderationItem{{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: n(0), StartAmount: n(100), EndAmount: n(100), Recipient: offerer}},
+		StartTime:     n(0),
+		EndTime:       n(1 << 40),
+		Salt:          n(1),
+		Counter:       n(0),
+	}
+
+	offer := listing
+	offer.Offer = []abi.OfferItem{{ItemType: abi.ItemTypeERC20, Token: common.HexToAddress("0x0000000000000000000000000000000000000e7e"), IdentifierOrCriteria: n(0), StartAmount: n(90), EndAmount: n(90)}}
+	offer.Consideration = []abi.ConsiderationItem{{ItemType: abi.ItemTypeERC721, Token: collection, IdentifierOrCriteria: n(1), StartAmount: n(1), EndAmount: n(1), Recipient: offerer}}
+
+	store := db.NewMemoryStore()
+	for _, c := range []abi.OrderComponents{listing, offer} {
+		c := c
+		if err := store.PutOrder(context.Background(), db.NewOrderFromComponents(d, order.Hash(&c), c, make([]byte, 65))); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+	}
+
+	return store
+}
+
+func TestGetOrdersSide(t *testing.T) {
+	s, err := NewServer(sidesStore(t), 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"listing", "offer"}},
+		{"?side=listing", []string{"listing"}},
+		{"?side=offer", []string{"offer"}},
+		{"?side=OFFER", []string{"offer"}},
+	}
+
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders"+tc.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /orders%s = %d: %s", tc.query, rec.Code, rec.Body)
+		}
+
+		var page ordersPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+
+		sides := []string{}
+		for _, o := range page.Orders {
+			sides = append(sides, o.Side)
+		}
+		sort.Strings(sides)
+
+		if strings.Join(sides, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("GET /orders%s returned %v, want %v", tc.query, sides, tc.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders?side=bid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET /orders with an unknown side = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"goport/abi"
+	"goport/bus"
+	"goport/db"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// Timestamps are formatted as RFC 3339 in UTC
+const timeFormat = time.RFC3339
+
+// Serves the stored orders and events over HTTP
+type Server struct {
+	Store db.Store
+
+	// Chain of the order lookups that do not set one
+	ChainID int64
+
+	// Accepts the orders posted to the API, submission is disabled if nil
+	Submitter OrderSubmitter
+
+	// New orders and events streamed to clients, streaming is disabled if nil
+	Bus *bus.Bus
+
+	mux *http.ServeMux
+}
+
+// Validates, stores and gossips submitted orders. Orders that fail validation are rejected with an
+// *order.ValidationError and orders of chains that are not followed with order.ErrNoDeployment.
+type OrderSubmitter interface {
+	SubmitOrder(ctx context.Context, chainID int64, c *abi.OrderComponents, signature []byte) (*db.Order, error)
+
+	// Stores the token ids of a criteria item, returns their merkle root
+	AddCriteria(ctx context.Context, tokenIDs []*big.Int) (common.Hash, error)
+}
+
+// Creates a new Server serving the GraphQL API on /graphql, the REST API and the streaming API
+func NewServer(store db.Store, chainID int64) (*Server, error) {
+	s := &Server{
+		Store:   store,
+		ChainID: chainID,
+		mux:     http.NewServeMux(),
+	}
+
+	gql, err := graphql.ParseSchema(schema, &resolver{s}, graphql.MaxDepth(10))
+	if err != nil {
+		log.Printf("Failed to parse GraphQL schema: %v", err.Error())
+		return nil, err
+	}
+
+	s.mux.Handle("/graphql", &relay.Handler{Schema: gql})
+	s.handleREST(s.mux)
+	s.handleStreams(s.mux)
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Listens on the address in the background
+func (s *Server) Start(wg *sync.WaitGroup, addr string) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		log.Printf("Serving the API on %s", addr)
+
+		if err := http.ListenAndServe(addr, s); err != nil {
+			log.Printf("API server failed: %v", err.Error())
+		}
+	}()
+}
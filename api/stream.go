@@ -0,0 +1,255 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goport/bus"
+	"goport/db"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time between keep-alives sent to streaming clients
+	pingInterval = 30 * time.Second
+
+	// Time a WebSocket client has to answer a ping
+	pongWait = 2 * pingInterval
+
+	// Time allowed to write a message to a streaming client
+	streamWriteWait = 10 * time.Second
+)
+
+var ErrStreamingDisabled = errors.New("streaming is disabled")
+
+// Message types clients can subscribe to
+var streamTypes = []string{
+	bus.TypeOrder,
+	db.EventOrderFulfilled,
+	db.EventOrderCancelled,
+	db.EventOrderValidated,
+	db.EventCounterIncremented,
+}
+
+// Streams are read-only and carry public data, so they can be opened from any origin
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Message sent to streaming clients, either order or event is set
+type streamMessageJSON struct {
+	Type       string          `json:"type"`
+	ChainID    int64           `json:"chainId"`
+	Collection *common.Address `json:"collection,omitempty"`
+	Order      *orderJSON      `json:"order,omitempty"`
+	Event      *eventJSON      `json:"event,omitempty"`
+}
+
+func newStreamMessageJSON(m *bus.Message) *streamMessageJSON {
+	j := &streamMessageJSON{
+		Type:    m.Type,
+		ChainID: m.ChainID,
+	}
+
+	if m.Collection != (common.Address{}) {
+		collection := m.Collection
+		j.Collection = &collection
+	}
+
+	if m.Order != nil {
+		j.Order = newOrderJSON(m.Order)
+	}
+
+	if m.Event != nil {
+		j.Event = newEventJSON(m.Event)
+	}
+
+	return j
+}
+
+// Routes the streaming API, both endpoints take the same filters:
+//
+//	GET /stream  Server-Sent Events
+//	GET /ws      WebSocket
+func (s *Server) handleStreams(mux *http.ServeMux) {
+	mux.HandleFunc("/stream", s.serveSSE)
+	mux.HandleFunc("/ws", s.serveWebSocket)
+}
+
+// Streams the messages matching the request filters as Server-Sent Events, each as a JSON data line
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	sub, err := s.subscribe(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("response writer does not support flushing"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case m, ok := <-sub.Messages():
+			if !ok {
+				b, _ := json.Marshal(map[string]string{"error": bus.ErrSlowSubscriber.Error()})
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+				flusher.Flush()
+				return
+			}
+
+			b, err := json.Marshal(newStreamMessageJSON(m))
+			if err != nil {
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+				return
+			}
+
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+
+		case <-r.Context().Done():
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// Streams the messages matching the request filters over a WebSocket, each as a JSON text message
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	sub, err := s.subscribe(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	// Upgrade replies to the client itself if it fails
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Clients only send control frames, reading handles them and notices when the connection closes
+	closed := make(chan struct{})
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	go func() {
+		defer close(closed)
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+
+		select {
+		case m, ok := <-sub.Messages():
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, bus.ErrSlowSubscriber.Error()))
+				return
+			}
+
+			if err := conn.WriteJSON(newStreamMessageJSON(m)); err != nil {
+				return
+			}
+
+		case <-ping.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-closed:
+			return
+		}
+	}
+}
+
+// Subscribes to the bus with the filters of a streaming request
+func (s *Server) subscribe(r *http.Request) (*bus.Subscription, error) {
+	if s.Bus == nil {
+		return nil, ErrStreamingDisabled
+	}
+
+	f, err := streamFilter(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Bus.Subscribe(f), nil
+}
+
+// Returns the filter of a streaming request, type is a comma separated list of message types
+func streamFilter(params url.Values) (bus.Filter, error) {
+	f := bus.Filter{}
+
+	var err error
+
+	if f.ChainID, err = intParam(params, "chainId"); err != nil {
+		return f, err
+	}
+
+	if v := params.Get("collection"); v != "" {
+		if f.Collection, err = parseAddress(v); err != nil {
+			return f, err
+		}
+	}
+
+	if v := params.Get("offerer"); v != "" {
+		if f.Offerer, err = parseAddress(v); err != nil {
+			return f, err
+		}
+	}
+
+	if v := params.Get("type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			if _, err := enumIndex(streamTypes, t); err != nil {
+				return f, err
+			}
+
+			f.Types = append(f.Types, t)
+		}
+	}
+
+	return f, nil
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"goport/bus"
+	"goport/db"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Starts a server streaming the messages of its bus, returns it with an order of chain 1 and a
+// copy of it on chain 2
+func newStreamServer(t *testing.T) (*httptest.Server, *bus.Bus, *db.Order, *db.Order) {
+	t.Helper()
+
+	store := sidesStore(t)
+	orders, err := store.QueryOrders(context.Background(), db.OrderQuery{ChainID: 1})
+	if err != nil || len(orders) == 0 {
+		t.Fatalf("QueryOrders: %v", err)
+	}
+
+	s, err := NewServer(store, 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	s.Bus = bus.New(16)
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	other := *orders[0]
+	other.ChainID = 2
+
+	return server, s.Bus, orders[0], &other
+}
+
+// Waits until the bus has n subscribers
+func waitSubscribers(t *testing.T, b *bus.Bus, n int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if b.Len() == n {
+			return
+		}
+	}
+
+	t.Fatalf("bus has %d subscribers, want %d", b.Len(), n)
+}
+
+func TestStreamSSE(t *testing.T) {
+	server, b, o, other := newStreamServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream?chainId=1&type=Order", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /stream: %v", err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type %q, want text/event-stream", ct)
+	}
+
+	waitSubscribers(t, b, 1)
+
+	// The order of the other chain is filtered out
+	b.Publish(bus.NewOrderMessage(other))
+	b.Publish(bus.NewOrderMessage(o))
+
+	streamed := false
+
+	lines := bufio.NewScanner(res.Body)
+	for !streamed && lines.Scan() {
+		data := strings.TrimPrefix(lines.Text(), "data: ")
+		if data == lines.Text() {
+			continue
+		}
+
+		var m streamMessageJSON
+		if err := json.Unmarshal([]byte(data), &m); err != nil {
+			t.Fatalf("invalid message %s: %v", data, err)
+		}
+		if m.Type != bus.TypeOrder || m.ChainID != 1 || m.Order == nil || m.Order.Hash != o.Hash {
+			t.Fatalf("streamed %s, want the order of chain 1", data)
+		}
+		streamed = true
+	}
+
+	if !streamed {
+		t.Fatalf("stream ended without a message: %v", lines.Err())
+	}
+
+	// Disconnecting unsubscribes the client
+	cancel()
+	waitSubscribers(t, b, 0)
+}
+
+func TestStreamWebSocket(t *testing.T) {
+	server, b, o, other := newStreamServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?chainId=1&collection="+o.Collection.Hex(), nil)
+	if err != nil {
+		t.Fatalf("Dial /ws: %v", err)
+	}
+	defer conn.Close()
+
+	waitSubscribers(t, b, 1)
+
+	b.Publish(bus.NewOrderMessage(other))
+	b.Publish(bus.NewOrderMessage(o))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var m streamMessageJSON
+	if err := conn.ReadJSON(&m); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if m.Type != bus.TypeOrder || m.ChainID != 1 || m.Collection == nil || *m.Collection != o.Collection || m.Order.Hash != o.Hash {
+		t.Fatalf("streamed %+v, want the order of chain 1", m)
+	}
+
+	// Closing the connection unsubscribes the client
+	conn.Close()
+	waitSubscribers(t, b, 0)
+}
+
+func TestStreamErrors(t *testing.T) {
+	server, _, _, _ := newStreamServer(t)
+
+	res, err := http.Get(server.URL + "/stream?type=Transfer")
+	if err != nil {
+		t.Fatalf("GET /stream: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("GET /stream of an unknown type = %d, want %d", res.StatusCode, http.StatusBadRequest)
+	}
+
+	// Streaming is disabled without a bus
+	s, err := NewServer(db.NewMemoryStore(), 1)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("GET /ws without a bus = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
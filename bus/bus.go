@@ -0,0 +1,199 @@
+package bus
+
+import (
+	"errors"
+	"goport/db"
+	"log"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Type of the messages of new orders, the messages of Seaport events have the type of the event
+const TypeOrder = "Order"
+
+// Number of messages a subscriber can fall behind before it is dropped
+const DefaultBuffer = 256
+
+var ErrSlowSubscriber = errors.New("subscriber fell behind")
+
+// New order or Seaport event published on the bus
+type Message struct {
+	// TypeOrder or one of the db Event constants
+	Type    string
+	ChainID int64
+
+	// Collection of the order, zero if it is unknown or the event has no order
+	Collection common.Address
+	Offerer    common.Address
+
+	// Set for TypeOrder messages
+	Order *db.Order
+
+	// Set for the messages of Seaport events
+	Event *db.EventRecord
+}
+
+// Creates the message of a new order
+func NewOrderMessage(o *db.Order) *Message {
+	return &Message{
+		Type:       TypeOrder,
+		ChainID:    o.ChainID,
+		Collection: o.Collection,
+		Offerer:    o.Offerer,
+		Order:      o,
+	}
+}
+
+// Creates the message of a written Seaport event, collection is the collection of its order
+func NewEventMessage(e *db.EventRecord, collection common.Address) *Message {
+	return &Message{
+		Type:       e.Type,
+		ChainID:    e.ChainID,
+		Collection: collection,
+		Offerer:    e.Offerer,
+		Event:      e,
+	}
+}
+
+// Messages a subscriber receives, zero fields match every message
+type Filter struct {
+	ChainID int64
+
+	// TypeOrder or db Event constants
+	Types []string
+
+	Collection common.Address
+	Offerer    common.Address
+}
+
+// Returns true if the message passes the filter
+func (f Filter) Match(m *Message) bool {
+	if f.ChainID != 0 && f.ChainID != m.ChainID {
+		return false
+	}
+
+	if f.Collection != (common.Address{}) && f.Collection != m.Collection {
+		return false
+	}
+
+	if f.Offerer != (common.Address{}) && f.Offerer != m.Offerer {
+		return false
+	}
+
+	if len(f.Types) == 0 {
+		return true
+	}
+
+	for _, t := range f.Types {
+		if t == m.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fans out the orders and Seaport events of the node to its subscribers. Publishing never blocks,
+// subscribers that fall behind by more than Buffer messages are dropped.
+type Bus struct {
+	Buffer int
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// Creates a new Bus
+func New(buffer int) *Bus {
+	if buffer < 1 {
+		buffer = DefaultBuffer
+	}
+
+	return &Bus{
+		Buffer: buffer,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Receives the messages matching a filter until it is unsubscribed or falls behind
+type Subscription struct {
+	Filter Filter
+
+	bus *Bus
+	c   chan *Message
+	err error
+}
+
+// Subscribes to the messages matching the filter
+func (b *Bus) Subscribe(f Filter) *Subscription {
+	s := &Subscription{
+		Filter: f,
+		bus:    b,
+		c:      make(chan *Message, b.Buffer),
+	}
+
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+
+	return s
+}
+
+// Sends a message to the matching subscribers
+func (b *Bus) Publish(m *Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if !s.Filter.Match(m) {
+			continue
+		}
+
+		select {
+		case s.c <- m:
+		default:
+			log.Printf("Dropping subscriber %d messages behind", len(s.c))
+			s.close(ErrSlowSubscriber)
+		}
+	}
+}
+
+// Returns the number of subscribers
+func (b *Bus) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subs)
+}
+
+// Returns the channel of the messages, it is closed when the subscription ends
+func (s *Subscription) Messages() <-chan *Message {
+	return s.c
+}
+
+// Returns ErrSlowSubscriber once the channel is closed if the subscriber fell behind
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.err
+}
+
+// Ends the subscription, it can be called more than once
+func (s *Subscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.close(nil)
+}
+
+// Must be called with the bus lock held
+func (s *Subscription) close(err error) {
+	if _, ok := s.bus.subs[s]; !ok {
+		return
+	}
+
+	delete(s.bus.subs, s)
+	s.err = err
+	close(s.c)
+}
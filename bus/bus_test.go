@@ -0,0 +1,142 @@
+package bus
+
+import (
+	"goport/db"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testCollection = common.HexToAddress("0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e")
+	testOfferer    = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func testOrderMessage(chainID int64, collection common.Address) *Message {
+	return NewOrderMessage(&db.Order{ChainID: chainID, Collection: collection, Offerer: testOfferer})
+}
+
+// Returns the messages already delivered to a subscription
+func pending(s *Subscription) []*Message {
+	messages := []*Message{}
+
+	for {
+		select {
+		case m, ok := <-s.Messages():
+			if !ok {
+				return messages
+			}
+			messages = append(messages, m)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	m := testOrderMessage(1, testCollection)
+	event := NewEventMessage(&db.EventRecord{Type: db.EventOrderCancelled, Offerer: testOfferer}, testCollection)
+
+	cases := []struct {
+		name  string
+		f     Filter
+		m     *Message
+		match bool
+	}{
+		{"empty", Filter{}, m, true},
+		{"chain", Filter{ChainID: 1}, m, true},
+		{"other chain", Filter{ChainID: 2}, m, false},
+		{"collection", Filter{Collection: testCollection}, m, true},
+		{"other collection", Filter{Collection: common.HexToAddress("0x01")}, m, false},
+		{"offerer", Filter{Offerer: testOfferer}, m, true},
+		{"other offerer", Filter{Offerer: common.HexToAddress("0x02")}, m, false},
+		{"order type", Filter{Types: []string{db.EventOrderCancelled, TypeOrder}}, m, true},
+		{"event type", Filter{Types: []string{db.EventOrderCancelled}}, event, true},
+		{"other type", Filter{Types: []string{db.EventOrderFulfilled}}, event, false},
+	}
+
+	for _, tc := range cases {
+		if match := tc.f.Match(tc.m); match != tc.match {
+			t.Errorf("%s: Match = %v, want %v", tc.name, match, tc.match)
+		}
+	}
+}
+
+func TestPublishFansOut(t *testing.T) {
+	b := New(4)
+
+	all := b.Subscribe(Filter{})
+	chain := b.Subscribe(Filter{ChainID: 1})
+	collection := b.Subscribe(Filter{ChainID: 1, Collection: testCollection})
+
+	b.Publish(testOrderMessage(1, testCollection))
+	b.Publish(testOrderMessage(1, common.Address{}))
+	b.Publish(testOrderMessage(2, testCollection))
+
+	for _, tc := range []struct {
+		name string
+		sub  *Subscription
+		want int
+	}{
+		{"all", all, 3},
+		{"chain", chain, 2},
+		{"collection", collection, 1},
+	} {
+		if n := len(pending(tc.sub)); n != tc.want {
+			t.Errorf("%s subscriber got %d messages, want %d", tc.name, n, tc.want)
+		}
+	}
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	b := New(2)
+
+	slow := b.Subscribe(Filter{})
+	other := b.Subscribe(Filter{ChainID: 2})
+
+	// The third message does not fit in the buffer of the slow subscriber
+	for i := 0; i < 3; i++ {
+		b.Publish(testOrderMessage(1, testCollection))
+	}
+
+	if n := len(pending(slow)); n != 2 {
+		t.Fatalf("slow subscriber got %d messages before being dropped, want 2", n)
+	}
+	if _, ok := <-slow.Messages(); ok {
+		t.Fatal("channel of the slow subscriber is still open")
+	}
+	if err := slow.Err(); err != ErrSlowSubscriber {
+		t.Fatalf("Err = %v, want ErrSlowSubscriber", err)
+	}
+
+	// Other subscribers are not affected
+	if n := b.Len(); n != 1 {
+		t.Fatalf("%d subscribers left, want 1", n)
+	}
+	b.Publish(testOrderMessage(2, testCollection))
+	if n := len(pending(other)); n != 1 {
+		t.Fatalf("other subscriber got %d messages, want 1", n)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New(2)
+
+	s := b.Subscribe(Filter{})
+	s.Unsubscribe()
+	s.Unsubscribe()
+
+	if n := b.Len(); n != 0 {
+		t.Fatalf("%d subscribers after unsubscribing, want 0", n)
+	}
+
+	// Messages published afterwards are not delivered to the closed channel
+	b.Publish(testOrderMessage(1, testCollection))
+
+	if _, ok := <-s.Messages(); ok {
+		t.Fatal("message delivered after unsubscribing")
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err = %v, want nil after unsubscribing", err)
+	}
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"goport/config"
+	"goport/internal/cli"
 	"goport/node"
 	"log"
+	"os"
 	"sync"
 
 	"github.com/libp2p/go-libp2p"
@@ -13,6 +15,19 @@ import (
 )
 
 func main() {
+	if err := config.Check(); err != nil {
+		log.Printf("Invalid configuration: %v", err.Error())
+		os.Exit(1)
+	}
+
+	// Commands run instead of the node, e.g. `goport migrate`
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1:]); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	wg := &sync.WaitGroup{}
 
 	log.Println("Starting node...")
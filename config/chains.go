@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Settings of a chain the node follows
+type Chain struct {
+	Name   string
+	RPCURL string
+	// How the listener follows the chain, see RPC_MODE
+	RPCMode string
+	// Expected chain id, checked against the RPC endpoint when set
+	ChainID uint64
+	// Number of blocks a Seaport event waits for before it is written
+	Confirmations uint64
+	// Backfill Seaport events starting at BackfillFromBlock, enabled when it is set
+	Backfill          bool
+	BackfillFromBlock uint64
+	// Seaport deployments to follow, as a version or version:address
+	SeaportDeployments []string
+}
+
+// Name of the chain configured by the single chain variables when CHAINS is unset
+const DefaultChain = "default"
+
+// Loads the chains listed in CHAINS, each configured by variables prefixed with its upper cased name
+// that default to the single chain variables, e.g. POLYGON_RPC_URL and POLYGON_CONFIRMATIONS.
+// Without CHAINS the single chain variables configure one chain.
+func loadChains() []Chain {
+	names := getEnvList("CHAINS", "")
+
+	if len(names) == 0 {
+		return []Chain{{
+			Name:               DefaultChain,
+			RPCURL:             getEnvRequired("RPC_URL"),
+			RPCMode:            RPC_MODE,
+			ChainID:            getEnvUint("CHAIN_ID", 0),
+			Confirmations:      CONFIRMATIONS,
+			Backfill:           BACKFILL,
+			BackfillFromBlock:  BACKFILL_FROM_BLOCK,
+			SeaportDeployments: SEAPORT_DEPLOYMENTS,
+		}}
+	}
+
+	chains := make([]Chain, len(names))
+	for i, name := range names {
+		prefix := strings.ToUpper(name) + "_"
+
+		chains[i] = Chain{
+			Name:               name,
+			RPCURL:             getEnvRequired(prefix + "RPC_URL"),
+			RPCMode:            getEnvOrDefault(prefix+"RPC_MODE", RPC_MODE),
+			ChainID:            getEnvUint(prefix+"CHAIN_ID", 0),
+			Confirmations:      getEnvUint(prefix+"CONFIRMATIONS", CONFIRMATIONS),
+			Backfill:           BACKFILL || os.Getenv(prefix+"BACKFILL_FROM_BLOCK") != "",
+			BackfillFromBlock:  getEnvUint(prefix+"BACKFILL_FROM_BLOCK", BACKFILL_FROM_BLOCK),
+			SeaportDeployments: getEnvList(prefix+"SEAPORT_DEPLOYMENTS", strings.Join(SEAPORT_DEPLOYMENTS, ",")),
+		}
+	}
+
+	return chains
+}
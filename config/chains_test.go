@@ -0,0 +1,102 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Sets the single chain variables for a test, like init does from the environment
+func setDefaults(t *testing.T) {
+	rpcMode, deployments, confirmations := RPC_MODE, SEAPORT_DEPLOYMENTS, CONFIRMATIONS
+	backfill, backfillFrom := BACKFILL, BACKFILL_FROM_BLOCK
+	t.Cleanup(func() {
+		RPC_MODE, SEAPORT_DEPLOYMENTS, CONFIRMATIONS = rpcMode, deployments, confirmations
+		BACKFILL, BACKFILL_FROM_BLOCK = backfill, backfillFrom
+	})
+
+	RPC_MODE = "auto"
+	SEAPORT_DEPLOYMENTS = []string{"1.6", "1.5"}
+	CONFIRMATIONS = 2
+	BACKFILL = true
+	BACKFILL_FROM_BLOCK = 100
+}
+
+func TestLoadChainsSingle(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CHAINS", "")
+	t.Setenv("RPC_URL", "wss://mainnet.example")
+	t.Setenv("CHAIN_ID", "1")
+
+	want := []Chain{{
+		Name:               DefaultChain,
+		RPCURL:             "wss://mainnet.example",
+		RPCMode:            "auto",
+		ChainID:            1,
+		Confirmations:      2,
+		Backfill:           true,
+		BackfillFromBlock:  100,
+		SeaportDeployments: []string{"1.6", "1.5"},
+	}}
+
+	if got := loadChains(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("loadChains = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadChainsNamed(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CHAINS", "mainnet, polygon")
+
+	t.Setenv("MAINNET_RPC_URL", "wss://mainnet.example")
+
+	t.Setenv("POLYGON_RPC_URL", "https://polygon.example")
+	t.Setenv("POLYGON_RPC_MODE", "poll")
+	t.Setenv("POLYGON_CHAIN_ID", "137")
+	t.Setenv("POLYGON_CONFIRMATIONS", "64")
+	t.Setenv("POLYGON_BACKFILL_FROM_BLOCK", "5000")
+	t.Setenv("POLYGON_SEAPORT_DEPLOYMENTS", "1.6")
+
+	want := []Chain{
+		// Unset variables default to the single chain variables
+		{
+			Name:               "mainnet",
+			RPCURL:             "wss://mainnet.example",
+			RPCMode:            "auto",
+			Confirmations:      2,
+			Backfill:           true,
+			BackfillFromBlock:  100,
+			SeaportDeployments: []string{"1.6", "1.5"},
+		},
+		{
+			Name:               "polygon",
+			RPCURL:             "https://polygon.example",
+			RPCMode:            "poll",
+			ChainID:            137,
+			Confirmations:      64,
+			Backfill:           true,
+			BackfillFromBlock:  5000,
+			SeaportDeployments: []string{"1.6"},
+		},
+	}
+
+	if got := loadChains(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("loadChains = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadChainsBackfillPerChain(t *testing.T) {
+	setDefaults(t)
+	BACKFILL, BACKFILL_FROM_BLOCK = false, 0
+
+	t.Setenv("CHAINS", "mainnet,polygon")
+	t.Setenv("MAINNET_RPC_URL", "wss://mainnet.example")
+	t.Setenv("POLYGON_RPC_URL", "https://polygon.example")
+	t.Setenv("POLYGON_BACKFILL_FROM_BLOCK", "0")
+
+	chains := loadChains()
+
+	// Backfilling from block 0 is enabled by setting the variable
+	if chains[0].Backfill || !chains[1].Backfill || chains[1].BackfillFromBlock != 0 {
+		t.Fatalf("backfill of mainnet %v and polygon %v from %d, want only polygon from 0", chains[0].Backfill, chains[1].Backfill, chains[1].BackfillFromBlock)
+	}
+}
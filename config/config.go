@@ -1,35 +1,139 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 var (
-	RPC_URL   string
-	DB_NAME   string
+	// Chains the node follows, see loadChains
+	CHAINS []Chain
+
+	// How the listener follows the chain, "subscribe", "poll" or "auto" to poll HTTP endpoints only
+	RPC_MODE string
+	// Polling interval and maximum number of blocks per eth_getLogs call in poll mode
+	POLL_INTERVAL    time.Duration
+	POLL_BLOCK_RANGE uint64
+
+	// Database dialect, "sqlite" or "postgres", and the SQLite database file or PostgreSQL connection URL
+	DB_DIALECT string
+	DB_NAME    string
+
+	// Maximum number of events written in one transaction and time between writes of partial batches
+	WRITE_BATCH_SIZE     uint64
+	WRITE_FLUSH_INTERVAL time.Duration
+
 	HOST_PORT string
 	HOST_NAME string
+
+	// Port of the HTTP API, disabled when unset
+	API_PORT string
+
+	// Seaport deployments to follow, as a version or version:address
+	SEAPORT_DEPLOYMENTS []string
+
+	// Collections to subscribe to, "*" subscribes to all collections
+	COLLECTIONS []string
+
+	// Number of blocks a Seaport event waits for before it is written
+	CONFIRMATIONS uint64
+
+	// Backfill Seaport events starting at BACKFILL_FROM_BLOCK, enabled when it is set
+	BACKFILL            bool
+	BACKFILL_FROM_BLOCK uint64
+	// Number of blocks requested at once while backfilling
+	BACKFILL_CHUNK_SIZE uint64
 )
 
-func getEnvOrFail(key string) string {
+// Required variables that are unset, reported by Check
+var missing []string
+
+// Returns an error naming the required environment variables that are unset. They are not checked
+// when the package loads so that packages importing it can be tested without them.
+func Check() error {
+	if len(missing) > 0 {
+		return fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func getEnvRequired(key string) string {
+	val := os.Getenv(key)
+	if val == "" {
+		missing = append(missing, key)
+	}
+	return val
+}
+
+func getEnvOrDefault(key string, def string) string {
 	val := os.Getenv(key)
 	if val == "" {
-		log.Fatalf("Missing environment variable: %s", key)
+		return def
 	}
 	return val
 }
 
+func getEnvList(key string, def string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnvOrDefault(key, def), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func getEnvUint(key string, def uint64) uint64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	n, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid environment variable %s: %v", key, err.Error())
+	}
+	return n
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Fatalf("Invalid environment variable %s: %v", key, err.Error())
+	}
+	return d
+}
+
 func init() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Printf("Error loading .env file: %v", err.Error())
 	}
 
-	RPC_URL = getEnvOrFail("RPC_URL")
-	DB_NAME = getEnvOrFail("DB_NAME")
-	HOST_PORT = getEnvOrFail("HOST_PORT")
-	HOST_NAME = getEnvOrFail("HOST_NAME")
+	RPC_MODE = getEnvOrDefault("RPC_MODE", "auto")
+	POLL_INTERVAL = getEnvDuration("POLL_INTERVAL", 12*time.Second)
+	POLL_BLOCK_RANGE = getEnvUint("POLL_BLOCK_RANGE", 1000)
+	DB_DIALECT = getEnvOrDefault("DB_DIALECT", "sqlite")
+	DB_NAME = getEnvRequired("DB_NAME")
+	WRITE_BATCH_SIZE = getEnvUint("WRITE_BATCH_SIZE", 500)
+	WRITE_FLUSH_INTERVAL = getEnvDuration("WRITE_FLUSH_INTERVAL", time.Second)
+	HOST_PORT = getEnvRequired("HOST_PORT")
+	HOST_NAME = getEnvRequired("HOST_NAME")
+	API_PORT = os.Getenv("API_PORT")
+	SEAPORT_DEPLOYMENTS = getEnvList("SEAPORT_DEPLOYMENTS", "1.6,1.5,1.4,1.1")
+	COLLECTIONS = getEnvList("COLLECTIONS", "*")
+	CONFIRMATIONS = getEnvUint("CONFIRMATIONS", 0)
+	BACKFILL = os.Getenv("BACKFILL_FROM_BLOCK") != ""
+	BACKFILL_FROM_BLOCK = getEnvUint("BACKFILL_FROM_BLOCK", 0)
+	BACKFILL_CHUNK_SIZE = getEnvUint("BACKFILL_CHUNK_SIZE", 2000)
+	CHAINS = loadChains()
 }
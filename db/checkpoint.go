@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Returns the last block of the chain processed by the named scanner, ok is false if it never ran
+func (s *SQLWrapper) Checkpoint(ctx context.Context, chainID int64, name string) (block uint64, ok bool, err error) {
+	c := new(Checkpoint)
+
+	err = s.DB.NewSelect().Model(c).Where("chain_id = ?", chainID).Where("name = ?", name).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return c.BlockNumber, true, nil
+}
+
+// Records the last block of the chain processed by the named scanner
+func (s *SQLWrapper) PutCheckpoint(ctx context.Context, chainID int64, name string, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return putCheckpoint(ctx, s.DB, CheckpointKey{chainID, name}, block)
+}
+
+func putCheckpoint(ctx context.Context, db bun.IDB, key CheckpointKey, block uint64) error {
+	c := &Checkpoint{
+		ChainID:     key.ChainID,
+		Name:        key.Name,
+		BlockNumber: block,
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := db.NewInsert().
+		Model(c).
+		On("CONFLICT (chain_id, name) DO UPDATE").
+		Set("block_number = EXCLUDED.block_number").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+
+	return err
+}
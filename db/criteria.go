@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"goport/order"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCriteriaNotFound = errors.New("criteria not found")
+	ErrCriteriaMismatch = errors.New("token ids do not match the criteria root")
+)
+
+// Token ids of a criteria merkle root
+type Criteria struct {
+	bun.BaseModel `bun:"table:criteria"`
+
+	Root      common.Hash `bun:"type:bytea,pk"`
+	TokenIDs  []*Uint256  `bun:"token_ids,type:jsonb,notnull"`
+	CreatedAt time.Time   `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// Creates the Criteria of a list of token ids after checking that they hash to the root
+func NewCriteria(root common.Hash, tokenIDs []*big.Int) (*Criteria, error) {
+	if root == (common.Hash{}) || order.CriteriaRoot(tokenIDs) != root {
+		return nil, ErrCriteriaMismatch
+	}
+
+	c := &Criteria{Root: root, TokenIDs: make([]*Uint256, len(tokenIDs))}
+	for i, id := range tokenIDs {
+		c.TokenIDs[i] = NewUint256(id)
+	}
+
+	return c, nil
+}
+
+// Returns the token ids as big integers
+func (c *Criteria) Ints() []*big.Int {
+	ids := make([]*big.Int, len(c.TokenIDs))
+	for i, id := range c.TokenIDs {
+		ids[i] = new(big.Int).Set(id.Int())
+	}
+
+	return ids
+}
+
+// Stores the token ids of a criteria root, roots already stored are ignored
+func (s *SQLWrapper) PutCriteria(ctx context.Context, root common.Hash, tokenIDs []*big.Int) error {
+	c, err := NewCriteria(root, tokenIDs)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.DB.NewInsert().Model(c).Ignore().Exec(ctx); err != nil {
+		log.Printf("Failed to write criteria %s to database: %v", root.Hex(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Returns the token ids of a criteria root, or ErrCriteriaNotFound
+func (s *SQLWrapper) GetCriteria(ctx context.Context, root common.Hash) ([]*big.Int, error) {
+	c := new(Criteria)
+
+	err := s.DB.NewSelect().Model(c).Where("root = ?", root).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCriteriaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Ints(), nil
+}
@@ -2,6 +2,9 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"goport/abi"
 	"log"
 	"sync"
@@ -9,72 +12,250 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// SQL implementation of Store
 type SQLWrapper struct {
 	DB *bun.DB
-	sync.Mutex
+
+	// Serializes writes, SQLite only allows a single writer
+	mu sync.Mutex
+}
+
+var _ Store = (*SQLWrapper)(nil)
+
+// Opens the database with the given dialect, name is the database file for SQLite and the
+// connection URL for PostgreSQL
+func Open(dialect string, name string) (*SQLWrapper, error) {
+	open, ok := dialects[dialect]
+	if !ok {
+		log.Printf("Unknown database dialect %q, supported dialects are %v", dialect, Dialects())
+		return nil, ErrUnknownDialect
+	}
+
+	db, err := open(name)
+	if err != nil {
+		log.Printf("Failed to connect to the database: %v", err.Error())
+		return nil, err
+	}
+
+	return &SQLWrapper{
+		DB: db,
+	}, nil
+}
+
+// Writes a Seaport event and applies it to the orders it affects. Events are identified by their
+// chain, transaction and log index, replayed events are ignored.
+func (s *SQLWrapper) ApplyEvent(ctx context.Context, e *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return applyEvent(ctx, tx, e)
+	})
+}
+
+// Writes the events and checkpoints of a batch in a single transaction, nothing is written if any
+// of them fails
+func (s *SQLWrapper) WriteBatch(ctx context.Context, b *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, e := range b.Events {
+			if err := applyEvent(ctx, tx, e); err != nil {
+				return err
+			}
+		}
+
+		for key, block := range b.Checkpoints {
+			if err := putCheckpoint(ctx, tx, key, block); err != nil {
+				log.Printf("Failed to write checkpoint %s of chain %d to database: %v", key.Name, key.ChainID, err.Error())
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func applyEvent(ctx context.Context, tx bun.Tx, e *Event) error {
+	switch event := e.Data.(type) {
+	case *abi.SeaportCounterIncremented:
+		return writeCounterIncremented(ctx, tx, e, event)
+	case *abi.SeaportOrderFulfilled:
+		return writeOrderFulfilled(ctx, tx, e, event)
+	case *abi.SeaportOrderCancelled:
+		return writeOrderCancelled(ctx, tx, e, event)
+	case *abi.SeaportOrderValidated:
+		return writeOrderValidated(ctx, tx, e, event)
+	}
+
+	return ErrUnsupportedEvent
+}
+
+// Inserts an event row, inserted is false if the event was already stored
+func insertEvent(ctx context.Context, tx bun.Tx, model interface{}) (inserted bool, err error) {
+	res, err := tx.NewInsert().Model(model).Ignore().Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+
+	return n > 0, err
 }
 
-func (s *SQLWrapper) WriteCounterIncremented(event *abi.SeaportCounterIncremented) error {
+// Writes the event and marks the offerer's orders signed with an older counter as stale
+func writeCounterIncremented(ctx context.Context, tx bun.Tx, e *Event, event *abi.SeaportCounterIncremented) error {
 	ic := &CounterIncremented{
-		Counter: event.NewCounter,
-		Offerer: event.Offerer,
+		EventLog: NewEventLog(e),
+		Counter:  NewUint256(event.NewCounter),
+		Offerer:  event.Offerer,
 	}
 
-	_, err := s.DB.NewInsert().Model(ic).Exec(context.Background(), s.DB)
+	inserted, err := insertEvent(ctx, tx, ic)
 	if err != nil {
+		log.Printf("Failed to write counter incremented to database: %v", err.Error())
 		return err
 	}
+	if !inserted {
+		return nil
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*Order)(nil)).
+		Set("status = ?", StatusStaleCounter).
+		Where("chain_id = ?", ic.ChainID).
+		Where("seaport = ?", ic.Seaport).
+		Where("offerer = ?", event.Offerer).
+		Where("counter < ?", ic.Counter).
+		Where("status IN (?)", bun.In(RevalidatedStatuses)).
+		Exec(ctx)
 
-	return nil
+	return err
 }
 
-func (s *SQLWrapper) WriteOrderFulfilled(event *abi.SeaportOrderFulfilled) error {
+// Writes the event and records the fill on the order, see fillAfter. If the fill of a partially
+// fillable order cannot be read from the event, only orders that cannot be partially filled are
+// marked as filled.
+func writeOrderFulfilled(ctx context.Context, tx bun.Tx, e *Event, event *abi.SeaportOrderFulfilled) error {
 	f := &FulfilledOrder{
+		EventLog:      NewEventLog(e),
 		Hash:          event.OrderHash,
 		Offerer:       event.Offerer,
 		Zone:          event.Zone,
 		Recipient:     event.Recipient,
-		Offer:         event.Offer,
-		Consideration: event.Consideration,
+		Offer:         spentItems(event.Offer),
+		Consideration: receivedItems(event.Consideration),
 	}
 
-	_, err := s.DB.NewInsert().Model(f).Exec(context.Background(), s.DB)
+	inserted, err := insertEvent(ctx, tx, f)
 	if err != nil {
 		log.Printf("Failed to write fulfilled order to database: %v", err.Error())
 		return err
 	}
+	if !inserted {
+		return nil
+	}
+
+	o := new(Order)
+	err = selectOrders(tx, o).
+		Where("o.chain_id = ?", f.ChainID).
+		Where("o.hash = ?", f.Hash).
+		Where("o.seaport = ?", f.Seaport).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Fills of orders that are not stored are only recorded as events
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to get fulfilled order from database: %v", err.Error())
+		return err
+	}
 
-	return nil
+	// Backfilled fills can be written after later ones, filled_at keeps the time of the last fill
+	q := tx.NewUpdate().
+		Model((*Order)(nil)).
+		Set(latestTime("filled_at"), e.Time()).
+		Where("chain_id = ?", f.ChainID).
+		Where("hash = ?", f.Hash).
+		Where("seaport = ?", f.Seaport)
+
+	if filled, size, ok := fillAfter(o, event); ok {
+		q = q.Set("total_filled = ?", NewUint256(filled)).
+			Set("total_size = ?", NewUint256(size))
+
+		if filled.Cmp(size) >= 0 {
+			q = q.Set("status = ?", StatusFilled)
+		}
+	} else if o.OrderType == abi.OrderTypeFullOpen || o.OrderType == abi.OrderTypeFullRestricted {
+		q = q.Set("status = ?", StatusFilled)
+	}
+
+	_, err = q.Exec(ctx)
+
+	return err
 }
 
-func (s *SQLWrapper) WriteOrderCancelled(event *abi.SeaportOrderCancelled) error {
+// Writes the event and marks the order as cancelled
+func writeOrderCancelled(ctx context.Context, tx bun.Tx, e *Event, event *abi.SeaportOrderCancelled) error {
 	o := &CancelledOrder{
-		Hash:    event.OrderHash,
-		Offerer: event.Offerer,
-		Zone:    event.Zone,
+		EventLog: NewEventLog(e),
+		Hash:     event.OrderHash,
+		Offerer:  event.Offerer,
+		Zone:     event.Zone,
 	}
 
-	_, err := s.DB.NewInsert().Model(o).Exec(context.Background(), s.DB)
+	inserted, err := insertEvent(ctx, tx, o)
 	if err != nil {
 		log.Printf("Failed to write cancelled order to database: %v", err.Error())
 		return err
 	}
+	if !inserted {
+		return nil
+	}
 
-	return nil
+	_, err = tx.NewUpdate().
+		Model((*Order)(nil)).
+		Set("status = ?", StatusCancelled).
+		Where("chain_id = ?", o.ChainID).
+		Where("hash = ?", o.Hash).
+		Where("seaport = ?", o.Seaport).
+		Exec(ctx)
+
+	return err
+}
+
+// Returns the assignment of a time column that keeps the later of its value and the argument
+func latestTime(column string) string {
+	return fmt.Sprintf("%[1]s = CASE WHEN %[1]s IS NULL OR %[1]s < ?0 THEN ?0 ELSE %[1]s END", column)
 }
 
-func (s *SQLWrapper) WriteOrderValidated(event *abi.SeaportOrderValidated) error {
+// Writes the event and marks the order as validated on-chain
+func writeOrderValidated(ctx context.Context, tx bun.Tx, e *Event, event *abi.SeaportOrderValidated) error {
 	v := &ValidatedOrder{
-		Hash:    event.OrderHash,
-		Offerer: event.Offerer,
-		Zone:    event.Zone,
+		EventLog: NewEventLog(e),
+		Hash:     event.OrderHash,
+		Offerer:  event.Offerer,
+		Zone:     event.Zone,
 	}
 
-	_, err := s.DB.NewInsert().Model(v).Exec(context.Background(), s.DB)
+	inserted, err := insertEvent(ctx, tx, v)
 	if err != nil {
 		log.Printf("Failed to write validated order to database: %v", err.Error())
 		return err
 	}
+	if !inserted {
+		return nil
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*Order)(nil)).
+		Set("is_validated = ?", true).
+		Set(latestTime("validated_at"), e.Time()).
+		Where("chain_id = ?", v.ChainID).
+		Where("hash = ?", v.Hash).
+		Where("seaport = ?", v.Seaport).
+		Exec(ctx)
 
-	return nil
+	return err
 }
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEventTimes(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+		now := time.Now().Unix()
+
+		o := newTestListing(1, 100, now+3600)
+		if err := s.PutOrder(ctx, o); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+
+		filled := time.Unix(now-7200, 0).UTC()
+		validated := time.Unix(now-86400, 0).UTC()
+
+		events := []*Event{
+			newTestValidated(o, 1, 10, validated),
+			newTestFulfilled(o, 3, 30, filled),
+			// Written late by a backfill, the order keeps the time of the last fill
+			newTestFulfilled(o, 2, 20, filled.Add(-time.Hour)),
+		}
+
+		for _, e := range events {
+			if err := s.ApplyEvent(ctx, e); err != nil {
+				t.Fatalf("ApplyEvent: %v", err)
+			}
+		}
+
+		got, err := s.GetOrder(ctx, 1, o.Hash)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+
+		if !got.FilledAt.Equal(filled) {
+			t.Fatalf("filled at %v, want the block time %v", got.FilledAt, filled)
+		}
+
+		if !got.ValidatedAt.Equal(validated) {
+			t.Fatalf("validated at %v, want the block time %v", got.ValidatedAt, validated)
+		}
+	})
+}
+
+func TestWriteBatchAtomic(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		o := newTestListing(1, 100, time.Now().Unix()+3600)
+		if err := s.PutOrder(ctx, o); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+
+		b := NewBatch()
+		b.Events = []*Event{
+			newTestFulfilled(o, 1, 10, time.Now()),
+			{Domain: testDomain, Data: struct{}{}},
+		}
+		b.Checkpoints[CheckpointKey{1, "test"}] = 10
+
+		if err := s.WriteBatch(ctx, b); !errors.Is(err, ErrUnsupportedEvent) {
+			t.Fatalf("WriteBatch with an unsupported event: %v, want %v", err, ErrUnsupportedEvent)
+		}
+
+		events, err := s.QueryEvents(ctx, EventQuery{ChainID: 1})
+		if err != nil {
+			t.Fatalf("QueryEvents: %v", err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("failed batch wrote %d events, want none", len(events))
+		}
+
+		if _, ok, err := s.Checkpoint(ctx, 1, "test"); err != nil || ok {
+			t.Fatalf("failed batch wrote its checkpoint: %v, %v", ok, err)
+		}
+
+		got, err := s.GetOrder(ctx, 1, o.Hash)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+		if got.Status != o.Status || !got.FilledAt.IsZero() {
+			t.Fatalf("failed batch changed the order to %s filled at %v", got.Status, got.FilledAt)
+		}
+
+		// Without the unsupported event everything is written
+		b.Events = b.Events[:1]
+		if err := s.WriteBatch(ctx, b); err != nil {
+			t.Fatalf("WriteBatch: %v", err)
+		}
+
+		if block, ok, err := s.Checkpoint(ctx, 1, "test"); err != nil || !ok || block != 10 {
+			t.Fatalf("Checkpoint = %d, %v, %v, want 10", block, ok, err)
+		}
+	})
+}
+
+// Returns a partially fillable listing of 4 ERC-1155 tokens for 400 wei
+func newTestPartialListing(tokenID int64) *Order {
+	c := abi.OrderComponents{
+		Offerer: testOfferer,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC1155, Token: testCollection, IdentifierOrCriteria: big.NewInt(tokenID), StartAmount: big.NewInt(4), EndAmount: big.NewInt(4)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(400), EndAmount: big.NewInt(400), Recipient: testOfferer},
+		},
+		OrderType: abi.OrderTypePartialOpen,
+		StartTime: big.NewInt(0),
+		EndTime:   big.NewInt(time.Now().Unix() + 3600),
+		Salt:      big.NewInt(tokenID),
+		Counter:   new(big.Int),
+	}
+
+	return NewOrderFromComponents(testDomain, order.Hash(&c), c, []byte{1})
+}
+
+// Returns a fill of amount of the tokens of a partially fillable test listing
+func newTestPartialFill(o *Order, tx byte, block uint64, amount int64) *Event {
+	e := newTestFulfilled(o, tx, block, time.Now())
+	fulfilled := e.Data.(*abi.SeaportOrderFulfilled)
+	fulfilled.Offer = []abi.SpentItem{
+		{ItemType: abi.ItemTypeERC1155, Token: testCollection, Identifier: o.Offer[0].IdentifierOrCriteria.Int(), Amount: big.NewInt(amount)},
+	}
+	fulfilled.Consideration[0].Amount = big.NewInt(100 * amount)
+
+	return e
+}
+
+func TestPartialFills(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		o := newTestPartialListing(1)
+		if err := s.PutOrder(ctx, o); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+
+		steps := []struct {
+			amount int64
+			filled int64
+			size   int64
+			status string
+		}{
+			{1, 1, 4, StatusActive},
+			{1, 1, 2, StatusActive},
+			{2, 1, 1, StatusFilled},
+		}
+
+		for i, step := range steps {
+			if err := s.ApplyEvent(ctx, newTestPartialFill(o, byte(i+1), uint64(10*(i+1)), step.amount)); err != nil {
+				t.Fatalf("ApplyEvent: %v", err)
+			}
+
+			got, err := s.GetOrder(ctx, 1, o.Hash)
+			if err != nil {
+				t.Fatalf("GetOrder: %v", err)
+			}
+
+			if got.TotalFilled.Int().Int64() != step.filled || got.TotalSize.Int().Int64() != step.size || got.Status != step.status {
+				t.Fatalf("after filling %d: %s/%s %s, want %d/%d %s", step.amount, got.TotalFilled.Int(), got.TotalSize.Int(), got.Status, step.filled, step.size, step.status)
+			}
+		}
+
+		// A full order is filled by any fill
+		full := newTestListing(2, 100, time.Now().Unix()+3600)
+		if err := s.PutOrder(ctx, full); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+		if err := s.ApplyEvent(ctx, newTestFulfilled(full, 9, 90, time.Now())); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+
+		got, err := s.GetOrder(ctx, 1, full.Hash)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+		if got.Status != StatusFilled {
+			t.Fatalf("full order is %s after a fill, want %s", got.Status, StatusFilled)
+		}
+	})
+}
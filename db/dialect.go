@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+var ErrUnknownDialect = errors.New("unknown database dialect")
+
+// Connects to a database of a dialect
+type opener func(name string) (*bun.DB, error)
+
+// Dialects goport was built with. PostgreSQL is only built with the postgres build tag,
+// so that SQLite builds do not pull in its driver.
+var dialects = map[string]opener{
+	DialectSQLite: openSQLite,
+}
+
+// Returns the names of the dialects goport was built with
+func Dialects() []string {
+	names := make([]string, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func openSQLite(name string) (*bun.DB, error) {
+	s, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s:", name))
+	if err != nil {
+		return nil, err
+	}
+
+	return bun.NewDB(s, sqlitedialect.New()), nil
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"goport/abi"
+	"goport/order"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// Values around the bounds of 64 bit integers, up to the largest uint256
+var testBigValues = testUint256s[1:]
+
+func TestEventItemsJSON(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		o := newTestListing(1, 100, time.Now().Unix()+3600)
+		if err := s.PutOrder(ctx, o); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+
+		e := newTestFulfilled(o, 1, 10, time.Now())
+		fulfilled := e.Data.(*abi.SeaportOrderFulfilled)
+		fulfilled.Offer = []abi.SpentItem{
+			{ItemType: abi.ItemTypeERC721, Token: testCollection, Identifier: testBigValues[2], Amount: big.NewInt(1)},
+		}
+		fulfilled.Consideration = append(fulfilled.Consideration,
+			abi.ReceivedItem{ItemType: abi.ItemTypeERC20, Token: testCollection, Identifier: new(big.Int), Amount: testBigValues[1], Recipient: testOfferer})
+
+		if err := s.ApplyEvent(ctx, e); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+
+		events, err := s.QueryEvents(ctx, EventQuery{ChainID: 1, Collection: testCollection, Type: EventOrderFulfilled})
+		if err != nil {
+			t.Fatalf("QueryEvents: %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("QueryEvents returned %d events, want 1", len(events))
+		}
+
+		got := events[0]
+		if len(got.Offer) != 1 || got.Offer[0].Token != testCollection || got.Offer[0].Identifier.Int().Cmp(testBigValues[2]) != 0 {
+			t.Fatalf("offer items %+v do not match %+v", got.Offer, fulfilled.Offer)
+		}
+
+		if len(got.Consideration) != 2 || got.Consideration[1].Amount.Int().Cmp(testBigValues[1]) != 0 || got.Consideration[1].Recipient != testOfferer {
+			t.Fatalf("consideration items %+v do not match %+v", got.Consideration, fulfilled.Consideration)
+		}
+	})
+}
+
+func TestCriteriaJSON(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		ids := append([]*big.Int{big.NewInt(0), big.NewInt(7)}, testBigValues...)
+		root := order.CriteriaRoot(ids)
+
+		if err := s.PutCriteria(ctx, root, ids); err != nil {
+			t.Fatalf("PutCriteria: %v", err)
+		}
+
+		got, err := s.GetCriteria(ctx, root)
+		if err != nil {
+			t.Fatalf("GetCriteria: %v", err)
+		}
+
+		if len(got) != len(ids) {
+			t.Fatalf("GetCriteria returned %d ids, want %d", len(got), len(ids))
+		}
+		for i := range ids {
+			if got[i].Cmp(ids[i]) != 0 {
+				t.Fatalf("token id %d = %s, want %s", i, got[i], ids[i])
+			}
+		}
+	})
+}
+
+func TestLargeNumbers(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		// Block numbers are stored as signed 64 bit integers
+		if err := s.PutCheckpoint(ctx, 1, "test", math.MaxInt64); err != nil {
+			t.Fatalf("PutCheckpoint: %v", err)
+		}
+		if block, ok, err := s.Checkpoint(ctx, 1, "test"); err != nil || !ok || block != math.MaxInt64 {
+			t.Fatalf("Checkpoint = %d, %v, %v, want %d", block, ok, err, int64(math.MaxInt64))
+		}
+
+		// Prices are compared as 32 byte big-endian values
+		end := time.Now().Unix() + 3600
+		for i, price := range testBigValues {
+			o := newTestListing(int64(i+1), 1, end)
+			o.Price = NewUint256(price)
+
+			if err := s.PutOrder(ctx, o); err != nil {
+				t.Fatalf("PutOrder: %v", err)
+			}
+		}
+
+		q := OrderQuery{ChainID: 1, MinPrice: new(big.Int).Lsh(big.NewInt(1), 64), Sort: SortPriceLowToHigh}
+
+		orders, err := s.QueryOrders(ctx, q)
+		if err != nil {
+			t.Fatalf("QueryOrders: %v", err)
+		}
+
+		if len(orders) != 2 || orders[0].Price.Int().Cmp(testBigValues[1]) != 0 || orders[1].Price.Int().Cmp(testBigValues[2]) != 0 {
+			prices := make([]string, len(orders))
+			for i, o := range orders {
+				prices[i] = o.Price.Int().String()
+			}
+			t.Fatalf("orders priced at least 2^64 = %v, want [%s %s]", prices, testBigValues[1], testBigValues[2])
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"goport/abi"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/uptrace/bun"
+)
+
+// Kinds of stored Seaport events
+const (
+	EventOrderFulfilled     = "OrderFulfilled"
+	EventOrderCancelled     = "OrderCancelled"
+	EventOrderValidated     = "OrderValidated"
+	EventCounterIncremented = "CounterIncremented"
+)
+
+// Stored Seaport event of any kind, the fields that do not apply to its kind are zero
+type EventRecord struct {
+	EventLog
+
+	Type string
+
+	// Order the event is about, zero for counter increments
+	OrderHash common.Hash
+	Offerer   common.Address
+	Zone      common.Address
+
+	// Items exchanged by an OrderFulfilled event
+	Recipient     common.Address
+	Offer         []*SpentItem
+	Consideration []*ReceivedItem
+
+	// New counter of a CounterIncremented event
+	Counter *Uint256
+}
+
+// Filters and pagination of an event query, zero filters match every event. Events are sorted by
+// chain, block and log index, newest first.
+type EventQuery struct {
+	ChainID int64
+
+	// Events of the orders of a collection, counter increments have no order and never match
+	Collection common.Address
+	OrderHash  common.Hash
+	Offerer    common.Address
+
+	// One of the Event constants
+	Type string
+
+	Limit int
+
+	// Continue after this event of a previous page
+	After *EventCursor
+}
+
+// Position of an event in the results of a query
+type EventCursor struct {
+	ChainID     int64
+	BlockNumber uint64
+	LogIndex    uint
+}
+
+// Creates the EventRecord an event is stored as
+func newEventRecord(e *Event) (*EventRecord, error) {
+	r := &EventRecord{EventLog: NewEventLog(e)}
+
+	switch event := e.Data.(type) {
+	case *abi.SeaportCounterIncremented:
+		r.Type = EventCounterIncremented
+		r.Offerer = event.Offerer
+		r.Counter = NewUint256(event.NewCounter)
+	case *abi.SeaportOrderFulfilled:
+		r.Type = EventOrderFulfilled
+		r.OrderHash = event.OrderHash
+		r.Offerer = event.Offerer
+		r.Zone = event.Zone
+		r.Recipient = event.Recipient
+		r.Offer = spentItems(event.Offer)
+		r.Consideration = receivedItems(event.Consideration)
+	case *abi.SeaportOrderCancelled:
+		r.Type = EventOrderCancelled
+		r.OrderHash = event.OrderHash
+		r.Offerer = event.Offerer
+		r.Zone = event.Zone
+	case *abi.SeaportOrderValidated:
+		r.Type = EventOrderValidated
+		r.OrderHash = event.OrderHash
+		r.Offerer = event.Offerer
+		r.Zone = event.Zone
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	return r, nil
+}
+
+func spentItems(items []abi.SpentItem) []*SpentItem {
+	res := make([]*SpentItem, len(items))
+	for i, item := range items {
+		res[i] = &SpentItem{
+			ItemType:   item.ItemType,
+			Token:      item.Token,
+			Identifier: NewUint256(item.Identifier),
+			Amount:     NewUint256(item.Amount),
+		}
+	}
+
+	return res
+}
+
+func receivedItems(items []abi.ReceivedItem) []*ReceivedItem {
+	res := make([]*ReceivedItem, len(items))
+	for i, item := range items {
+		res[i] = &ReceivedItem{
+			ItemType:   item.ItemType,
+			Token:      item.Token,
+			Identifier: NewUint256(item.Identifier),
+			Amount:     NewUint256(item.Amount),
+			Recipient:  item.Recipient,
+		}
+	}
+
+	return res
+}
+
+// Returns the events matching the query from every event table
+func (s *SQLWrapper) QueryEvents(ctx context.Context, q EventQuery) ([]*EventRecord, error) {
+	events := []*EventRecord{}
+
+	if q.Type == "" || q.Type == EventOrderFulfilled {
+		var rows []*FulfilledOrder
+		if err := s.filterEvents(&rows, q, true).Scan(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			events = append(events, &EventRecord{
+				EventLog:      r.EventLog,
+				Type:          EventOrderFulfilled,
+				OrderHash:     r.Hash,
+				Offerer:       r.Offerer,
+				Zone:          r.Zone,
+				Recipient:     r.Recipient,
+				Offer:         r.Offer,
+				Consideration: r.Consideration,
+			})
+		}
+	}
+
+	if q.Type == "" || q.Type == EventOrderCancelled {
+		var rows []*CancelledOrder
+		if err := s.filterEvents(&rows, q, true).Scan(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			events = append(events, &EventRecord{EventLog: r.EventLog, Type: EventOrderCancelled, OrderHash: r.Hash, Offerer: r.Offerer, Zone: r.Zone})
+		}
+	}
+
+	if q.Type == "" || q.Type == EventOrderValidated {
+		var rows []*ValidatedOrder
+		if err := s.filterEvents(&rows, q, true).Scan(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			events = append(events, &EventRecord{EventLog: r.EventLog, Type: EventOrderValidated, OrderHash: r.Hash, Offerer: r.Offerer, Zone: r.Zone})
+		}
+	}
+
+	if (q.Type == "" || q.Type == EventCounterIncremented) && q.Collection == (common.Address{}) && q.OrderHash == (common.Hash{}) {
+		var rows []*CounterIncremented
+		if err := s.filterEvents(&rows, q, false).Scan(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			events = append(events, &EventRecord{EventLog: r.EventLog, Type: EventCounterIncremented, Offerer: r.Offerer, Counter: r.Counter})
+		}
+	}
+
+	// Every table returned up to a page, the page is made of the newest of them
+	sortEvents(events)
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[:q.Limit]
+	}
+
+	return events, nil
+}
+
+// Selects the events of a table matching the query, hasOrder is false for tables without an order hash
+func (s *SQLWrapper) filterEvents(model interface{}, q EventQuery, hasOrder bool) *bun.SelectQuery {
+	sq := s.DB.NewSelect().Model(model)
+
+	if q.ChainID != 0 {
+		sq = sq.Where("?TableAlias.chain_id = ?", q.ChainID)
+	}
+
+	if q.Offerer != (common.Address{}) {
+		sq = sq.Where("?TableAlias.offerer = ?", q.Offerer)
+	}
+
+	if hasOrder && q.OrderHash != (common.Hash{}) {
+		sq = sq.Where("?TableAlias.hash = ?", q.OrderHash)
+	}
+
+	if hasOrder && q.Collection != (common.Address{}) {
+		sq = sq.Where("EXISTS (SELECT 1 FROM orders AS o WHERE o.chain_id = ?TableAlias.chain_id AND o.hash = ?TableAlias.hash AND o.collection = ?)", q.Collection)
+	}
+
+	if q.After != nil {
+		sq = sq.Where("(?TableAlias.chain_id < ?0 OR (?TableAlias.chain_id = ?0 AND (?TableAlias.block_number < ?1 OR (?TableAlias.block_number = ?1 AND ?TableAlias.log_index < ?2))))",
+			q.After.ChainID, q.After.BlockNumber, q.After.LogIndex)
+	}
+
+	sq = sq.OrderExpr("?TableAlias.chain_id DESC").
+		OrderExpr("?TableAlias.block_number DESC").
+		OrderExpr("?TableAlias.log_index DESC")
+
+	if q.Limit > 0 {
+		sq = sq.Limit(q.Limit)
+	}
+
+	return sq
+}
+
+// Sorts events by chain, block and log index, newest first
+func sortEvents(events []*EventRecord) {
+	sort.Slice(events, func(i, j int) bool {
+		return compareEvents(events[i], events[j]) < 0
+	})
+}
+
+// Returns a negative number if x comes before y, newest first
+func compareEvents(x *EventRecord, y *EventRecord) int {
+	switch {
+	case x.ChainID != y.ChainID:
+		return -compareInts(x.ChainID, y.ChainID)
+	case x.BlockNumber != y.BlockNumber:
+		if x.BlockNumber > y.BlockNumber {
+			return -1
+		}
+		return 1
+	case x.LogIndex != y.LogIndex:
+		if x.LogIndex > y.LogIndex {
+			return -1
+		}
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"goport/abi"
+	"math/big"
+)
+
+// Returns the fill of a partially fillable order after an OrderFulfilled event, from the amounts of
+// the event. Seaport scales every item of a partial fill by the same fraction, which is read from the
+// first item whose amount does not change over time. ok is false if the order cannot be partially
+// filled or the fraction cannot be read exactly.
+func fillAfter(o *Order, event *abi.SeaportOrderFulfilled) (filled *big.Int, size *big.Int, ok bool) {
+	if o.OrderType != abi.OrderTypePartialOpen && o.OrderType != abi.OrderTypePartialRestricted {
+		return nil, nil, false
+	}
+
+	n, d, ok := fillFraction(o, event)
+	if !ok {
+		return nil, nil, false
+	}
+
+	filled, size = o.TotalFilled.Int(), o.TotalSize.Int()
+
+	// Like Seaport, fractions with the same denominator are added as they are
+	switch {
+	case size.Sign() == 0:
+		filled, size = n, d
+	case size.Cmp(d) == 0:
+		filled = new(big.Int).Add(filled, n)
+	default:
+		filled = new(big.Int).Add(new(big.Int).Mul(filled, d), new(big.Int).Mul(n, size))
+		size = new(big.Int).Mul(size, d)
+	}
+
+	if filled.Cmp(size) > 0 {
+		filled = size
+	}
+
+	gcd := new(big.Int).GCD(nil, nil, filled, size)
+	if gcd.Sign() > 0 {
+		filled = new(big.Int).Quo(filled, gcd)
+		size = new(big.Int).Quo(size, gcd)
+	}
+
+	return filled, size, true
+}
+
+// Returns the fraction of an order a fill spent, as the amount of an item over its amount in the order
+func fillFraction(o *Order, event *abi.SeaportOrderFulfilled) (*big.Int, *big.Int, bool) {
+	for i, item := range o.Offer {
+		if i < len(event.Offer) && item.StartAmount.Int().Cmp(item.EndAmount.Int()) == 0 && item.StartAmount.Int().Sign() > 0 {
+			return reduce(event.Offer[i].Amount, item.StartAmount.Int())
+		}
+	}
+
+	for i, item := range o.Consideration {
+		if i < len(event.Consideration) && item.StartAmount.Int().Cmp(item.EndAmount.Int()) == 0 && item.StartAmount.Int().Sign() > 0 {
+			return reduce(event.Consideration[i].Amount, item.StartAmount.Int())
+		}
+	}
+
+	return nil, nil, false
+}
+
+// Returns n/d in lowest terms, ok is false if it is not a fraction between 0 and 1
+func reduce(n *big.Int, d *big.Int) (*big.Int, *big.Int, bool) {
+	if n == nil || n.Sign() <= 0 || n.Cmp(d) > 0 {
+		return nil, nil, false
+	}
+
+	gcd := new(big.Int).GCD(nil, nil, n, d)
+
+	return new(big.Int).Quo(n, gcd), new(big.Int).Quo(d, gcd), true
+}
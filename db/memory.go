@@ -0,0 +1,596 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// In-memory implementation of Store, for tests and nodes that do not need to persist anything.
+// It applies events the same way the SQL implementation does.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	// Orders in the order they were stored, indexed by chain and hash
+	orders []*Order
+	index  map[orderKey]*Order
+
+	events      []*EventRecord
+	checkpoints map[CheckpointKey]uint64
+	criteria    map[common.Hash]*Criteria
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type orderKey struct {
+	chainID int64
+	hash    common.Hash
+}
+
+// Creates a new empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		index:       make(map[orderKey]*Order),
+		checkpoints: make(map[CheckpointKey]uint64),
+		criteria:    make(map[common.Hash]*Criteria),
+	}
+}
+
+// Stores a copy of the order, orders already stored are ignored
+func (m *MemoryStore) PutOrder(ctx context.Context, o *Order) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := orderKey{o.ChainID, o.Hash}
+	if _, ok := m.index[key]; ok {
+		return nil
+	}
+
+	c := *o
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+
+	m.orders = append(m.orders, &c)
+	m.index[key] = &c
+
+	return nil
+}
+
+func (m *MemoryStore) GetOrder(ctx context.Context, chainID int64, hash common.Hash) (*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	o, ok := m.index[orderKey{chainID, hash}]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+
+	c := *o
+
+	return &c, nil
+}
+
+func (m *MemoryStore) QueryOrders(ctx context.Context, q OrderQuery) ([]*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.query(q, true), nil
+}
+
+func (m *MemoryStore) QueryOrderHashes(ctx context.Context, q OrderQuery) ([]common.Hash, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orders := m.query(q, true)
+
+	hashes := make([]common.Hash, len(orders))
+	for i, o := range orders {
+		hashes[i] = o.Hash
+	}
+
+	return hashes, nil
+}
+
+func (m *MemoryStore) PutCriteria(ctx context.Context, root common.Hash, tokenIDs []*big.Int) error {
+	c, err := NewCriteria(root, tokenIDs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.criteria[root]; !ok {
+		m.criteria[root] = c
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) GetCriteria(ctx context.Context, root common.Hash) ([]*big.Int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c, ok := m.criteria[root]
+	if !ok {
+		return nil, ErrCriteriaNotFound
+	}
+
+	return c.Ints(), nil
+}
+
+func (m *MemoryStore) CountOrders(ctx context.Context, q OrderQuery) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.query(q, false)), nil
+}
+
+// Returns copies of the orders matching the query, sorted like the SQL implementation sorts them
+func (m *MemoryStore) query(q OrderQuery, paginate bool) []*Order {
+	orders := []*Order{}
+	now := big.NewInt(time.Now().Unix())
+
+	for _, o := range m.orders {
+		if (q.Side != nil && o.Side != *q.Side) ||
+			(q.ChainID != 0 && o.ChainID != q.ChainID) ||
+			(q.Collection != (common.Address{}) && o.Collection != q.Collection) ||
+			(q.Offerer != (common.Address{}) && o.Offerer != q.Offerer) ||
+			(q.Status != "" && o.Status != q.Status) ||
+			(q.Status == StatusActive && (o.EndTime.Int().Cmp(now) <= 0 || o.StartTime.Int().Cmp(now) > 0)) ||
+			(q.TokenID != nil && !hasToken(o, q.TokenID)) ||
+			(q.ItemType != nil && !hasItemType(o, *q.ItemType)) ||
+			(q.MinPrice != nil && o.Price.Int().Cmp(q.MinPrice) < 0) ||
+			(q.MaxPrice != nil && o.Price.Int().Cmp(q.MaxPrice) > 0) ||
+			(q.Sort == SortRecentlyFulfilled && o.FilledAt.IsZero()) ||
+			(q.Sort == SortRecentlyValidated && o.ValidatedAt.IsZero()) {
+			continue
+		}
+
+		c := *o
+		orders = append(orders, &c)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return compareOrders(q.Sort, orders[i], orders[j]) < 0
+	})
+
+	if q.After != nil {
+		c, ok := m.index[orderKey{q.After.ChainID, q.After.Hash}]
+		if !ok {
+			return []*Order{}
+		}
+
+		after := []*Order{}
+		for _, o := range orders {
+			if compareOrders(q.Sort, c, o) < 0 {
+				after = append(after, o)
+			}
+		}
+		orders = after
+	}
+
+	if !paginate {
+		return orders
+	}
+
+	if q.Offset >= len(orders) {
+		return []*Order{}
+	}
+	orders = orders[q.Offset:]
+
+	if q.Limit > 0 && q.Limit < len(orders) {
+		orders = orders[:q.Limit]
+	}
+
+	return orders
+}
+
+func (m *MemoryStore) UpdateOrderStatus(ctx context.Context, chainID int64, hash common.Hash, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if o, ok := m.index[orderKey{chainID, hash}]; ok {
+		o.Status = status
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) UpdateOrderValidation(ctx context.Context, chainID int64, hash common.Hash, res *order.ValidationResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.index[orderKey{chainID, hash}]
+	if !ok {
+		return nil
+	}
+
+	o.Status = StatusFromResult(res)
+	o.IsValidated = res.IsValidated
+	o.TotalFilled = NewUint256(res.TotalFilled)
+	o.TotalSize = NewUint256(res.TotalSize)
+
+	if !res.IsValidated {
+		o.ValidatedAt = time.Time{}
+	}
+
+	if res.TotalFilled.Sign() == 0 {
+		o.FilledAt = time.Time{}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) OfferTokens(ctx context.Context, chainID int64) ([]common.Address, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tokens := []common.Address{}
+	seen := make(map[common.Address]bool)
+
+	for _, o := range m.orders {
+		if o.ChainID != chainID || !containsString(RevalidatedStatuses, o.Status) {
+			continue
+		}
+
+		for _, item := range o.Offer {
+			if !seen[item.Token] {
+				seen[item.Token] = true
+				tokens = append(tokens, item.Token)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+func (m *MemoryStore) OrdersByOfferToken(ctx context.Context, chainID int64, offerers []common.Address, token common.Address) ([]*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orders := []*Order{}
+
+	for _, o := range m.orders {
+		if o.ChainID != chainID || !containsAddress(offerers, o.Offerer) || !containsString(RevalidatedStatuses, o.Status) {
+			continue
+		}
+
+		for _, item := range o.Offer {
+			if item.Token == token {
+				c := *o
+				orders = append(orders, &c)
+				break
+			}
+		}
+	}
+
+	return orders, nil
+}
+
+func (m *MemoryStore) StaleOrders(ctx context.Context, chainID int64, offerers []common.Address) ([]*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orders := []*Order{}
+
+	for _, o := range m.orders {
+		if o.ChainID == chainID && containsAddress(offerers, o.Offerer) && o.Status == StatusStaleCounter {
+			c := *o
+			orders = append(orders, &c)
+		}
+	}
+
+	return orders, nil
+}
+
+// Stores the event and applies it to the orders it affects
+func (m *MemoryStore) ApplyEvent(ctx context.Context, e *Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, err := newEventRecord(e)
+	if err != nil {
+		return err
+	}
+
+	m.applyEvent(e, stored)
+
+	return nil
+}
+
+// Stores the record of an event and applies the event, must be called with mu held
+func (m *MemoryStore) applyEvent(e *Event, stored *EventRecord) {
+	chainID := e.Domain.ChainID.Int64()
+
+	// Replayed events are ignored, like the SQL implementation does
+	for _, ev := range m.events {
+		if ev.ChainID == stored.ChainID && ev.TxHash == stored.TxHash && ev.LogIndex == stored.LogIndex {
+			return
+		}
+	}
+	m.events = append(m.events, stored)
+
+	if event, ok := e.Data.(*abi.SeaportCounterIncremented); ok {
+		for _, o := range m.orders {
+			if o.ChainID == chainID && o.Seaport == event.Raw.Address && o.Offerer == event.Offerer &&
+				o.Counter.Int().Cmp(event.NewCounter) < 0 && containsString(RevalidatedStatuses, o.Status) {
+				o.Status = StatusStaleCounter
+			}
+		}
+
+		return
+	}
+
+	o, ok := m.index[orderKey{chainID, stored.OrderHash}]
+	if !ok || o.Seaport != stored.Seaport {
+		return
+	}
+
+	switch event := e.Data.(type) {
+	case *abi.SeaportOrderFulfilled:
+		if t := e.Time(); t.After(o.FilledAt) {
+			o.FilledAt = t
+		}
+
+		if filled, size, ok := fillAfter(o, event); ok {
+			o.TotalFilled = NewUint256(filled)
+			o.TotalSize = NewUint256(size)
+
+			if filled.Cmp(size) >= 0 {
+				o.Status = StatusFilled
+			}
+		} else if o.OrderType == abi.OrderTypeFullOpen || o.OrderType == abi.OrderTypeFullRestricted {
+			o.Status = StatusFilled
+		}
+
+	case *abi.SeaportOrderCancelled:
+		o.Status = StatusCancelled
+
+	case *abi.SeaportOrderValidated:
+		o.IsValidated = true
+		if t := e.Time(); t.After(o.ValidatedAt) {
+			o.ValidatedAt = t
+		}
+	}
+}
+
+// Applies the events then writes the checkpoints of the batch. Every event is checked before any
+// is applied, so nothing is written if one of them is unsupported, like the SQL transaction.
+func (m *MemoryStore) WriteBatch(ctx context.Context, b *Batch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records := make([]*EventRecord, len(b.Events))
+	for i, e := range b.Events {
+		r, err := newEventRecord(e)
+		if err != nil {
+			return err
+		}
+		records[i] = r
+	}
+
+	for i, e := range b.Events {
+		m.applyEvent(e, records[i])
+	}
+
+	for key, block := range b.Checkpoints {
+		m.checkpoints[key] = block
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) QueryEvents(ctx context.Context, q EventQuery) ([]*EventRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	events := []*EventRecord{}
+
+	for _, e := range m.events {
+		if (q.ChainID != 0 && e.ChainID != q.ChainID) ||
+			(q.Offerer != (common.Address{}) && e.Offerer != q.Offerer) ||
+			(q.Type != "" && e.Type != q.Type) {
+			continue
+		}
+
+		if q.OrderHash != (common.Hash{}) && (e.Type == EventCounterIncremented || e.OrderHash != q.OrderHash) {
+			continue
+		}
+
+		if q.Collection != (common.Address{}) {
+			o, ok := m.index[orderKey{e.ChainID, e.OrderHash}]
+			if e.Type == EventCounterIncremented || !ok || o.Collection != q.Collection {
+				continue
+			}
+		}
+
+		if q.After != nil && compareEvents(&EventRecord{EventLog: EventLog{ChainID: q.After.ChainID, BlockNumber: q.After.BlockNumber, LogIndex: q.After.LogIndex}}, e) >= 0 {
+			continue
+		}
+
+		c := *e
+		events = append(events, &c)
+	}
+
+	sortEvents(events)
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[:q.Limit]
+	}
+
+	return events, nil
+}
+
+func (m *MemoryStore) EventBlocks(ctx context.Context, chainID int64, from uint64) (map[uint64][]common.Hash, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blocks := make(map[uint64][]common.Hash)
+
+	for _, e := range m.events {
+		if e.ChainID == chainID && e.BlockNumber >= from && !containsHash(blocks[e.BlockNumber], e.BlockHash) {
+			blocks[e.BlockNumber] = append(blocks[e.BlockNumber], e.BlockHash)
+		}
+	}
+
+	return blocks, nil
+}
+
+func (m *MemoryStore) RollbackBlock(ctx context.Context, chainID int64, blockHash common.Hash) (*Rollback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := &Rollback{}
+	kept := m.events[:0]
+
+	for _, e := range m.events {
+		if e.ChainID != chainID || e.BlockHash != blockHash {
+			kept = append(kept, e)
+			continue
+		}
+
+		if e.Type == EventCounterIncremented {
+			r.Offerers = append(r.Offerers, e.Offerer)
+		} else if !containsHash(r.Orders, e.OrderHash) {
+			r.Orders = append(r.Orders, e.OrderHash)
+		}
+	}
+	m.events = kept
+
+	return r, nil
+}
+
+func (m *MemoryStore) Checkpoint(ctx context.Context, chainID int64, name string) (uint64, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	block, ok := m.checkpoints[CheckpointKey{chainID, name}]
+
+	return block, ok, nil
+}
+
+func (m *MemoryStore) PutCheckpoint(ctx context.Context, chainID int64, name string, block uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checkpoints[CheckpointKey{chainID, name}] = block
+
+	return nil
+}
+
+// Returns a negative number if x comes before y in the sort order, like the SQL implementation
+// sorts them. Orders with the same value are sorted by chain id and hash in the same direction.
+func compareOrders(s OrderSort, x *Order, y *Order) int {
+	var c int
+
+	switch s {
+	case SortRecentlyFulfilled:
+		c = compareTimes(x.FilledAt, y.FilledAt)
+	case SortRecentlyValidated:
+		c = compareTimes(x.ValidatedAt, y.ValidatedAt)
+	case SortEndingSoon:
+		c = x.EndTime.Int().Cmp(y.EndTime.Int())
+	case SortPriceLowToHigh, SortPriceHighToLow:
+		c = x.Price.Int().Cmp(y.Price.Int())
+	default:
+		c = compareTimes(x.CreatedAt, y.CreatedAt)
+	}
+
+	if c == 0 {
+		c = compareInts(x.ChainID, y.ChainID)
+	}
+
+	if c == 0 {
+		c = bytes.Compare(x.Hash[:], y.Hash[:])
+	}
+
+	if _, desc := s.key(); desc {
+		return -c
+	}
+
+	return c
+}
+
+func compareTimes(x time.Time, y time.Time) int {
+	switch {
+	case x.Before(y):
+		return -1
+	case x.After(y):
+		return 1
+	}
+
+	return 0
+}
+
+func compareInts(x int64, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
+
+// Returns whether the order has an item of its collection with the identifier
+func hasToken(o *Order, id *big.Int) bool {
+	for _, item := range o.Offer {
+		if item.Token == o.Collection && item.IdentifierOrCriteria.Int().Cmp(id) == 0 {
+			return true
+		}
+	}
+
+	for _, item := range o.Consideration {
+		if item.Token == o.Collection && item.IdentifierOrCriteria.Int().Cmp(id) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether the order has an item of the type
+func hasItemType(o *Order, itemType uint8) bool {
+	for _, item := range o.Offer {
+		if item.ItemType == itemType {
+			return true
+		}
+	}
+
+	for _, item := range o.Consideration {
+		if item.ItemType == itemType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAddress(list []common.Address, a common.Address) bool {
+	for _, v := range list {
+		if v == a {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"goport/db/migrations"
+	"log"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+func (s *SQLWrapper) migrator(ctx context.Context) (*migrate.Migrator, error) {
+	m := migrate.NewMigrator(s.DB, migrations.Migrations)
+
+	// Creates the tables tracking applied migrations, if they do not exist yet
+	if err := m.Init(ctx); err != nil {
+		log.Printf("Failed to create the migration tables: %v", err.Error())
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Applies the pending schema migrations, locking out other nodes migrating the same database
+func (s *SQLWrapper) Migrate(ctx context.Context) error {
+	m, err := s.migrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Lock(ctx); err != nil {
+		log.Printf("Failed to lock the database for migration: %v", err.Error())
+		return err
+	}
+	defer m.Unlock(ctx)
+
+	group, err := m.Migrate(ctx)
+	if err != nil {
+		log.Printf("Failed to migrate the database: %v", err.Error())
+		return err
+	}
+
+	if group.IsZero() {
+		log.Printf("Database schema is up to date")
+	} else {
+		log.Printf("Migrated the database to %s", group)
+	}
+
+	return nil
+}
+
+// Reverts the last group of applied migrations
+func (s *SQLWrapper) RollbackMigrations(ctx context.Context) error {
+	m, err := s.migrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Lock(ctx); err != nil {
+		log.Printf("Failed to lock the database for migration: %v", err.Error())
+		return err
+	}
+	defer m.Unlock(ctx)
+
+	group, err := m.Rollback(ctx)
+	if err != nil {
+		log.Printf("Failed to roll back the database: %v", err.Error())
+		return err
+	}
+
+	if group.IsZero() {
+		log.Printf("No migrations to roll back")
+	} else {
+		log.Printf("Rolled back %s", group)
+	}
+
+	return nil
+}
+
+// Returns every known migration, with the applied ones marked
+func (s *SQLWrapper) Migrations(ctx context.Context) (migrate.MigrationSlice, error) {
+	m, err := s.migrator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.MigrationsWithStatus(ctx)
+}
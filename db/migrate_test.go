@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Returns the number of applied migrations
+func appliedMigrations(t *testing.T, s *SQLWrapper) int {
+	t.Helper()
+
+	ms, err := s.Migrations(context.Background())
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+
+	return len(ms.Applied())
+}
+
+func TestMigrateUpDownUp(t *testing.T) {
+	for name, open := range testDialects {
+		open := open
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			s := open(t)
+			t.Cleanup(func() { s.DB.Close() })
+
+			if err := s.Migrate(ctx); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+
+			all, err := s.Migrations(ctx)
+			if err != nil {
+				t.Fatalf("Migrations: %v", err)
+			}
+			if n := appliedMigrations(t, s); n == 0 || n != len(all) {
+				t.Fatalf("%d of %d migrations applied", n, len(all))
+			}
+
+			// Migrating an up to date database does nothing
+			if err := s.Migrate(ctx); err != nil {
+				t.Fatalf("Migrate of an up to date database: %v", err)
+			}
+			if n := appliedMigrations(t, s); n != len(all) {
+				t.Fatalf("%d of %d migrations applied after migrating again", n, len(all))
+			}
+
+			// The migrations of a fresh database are applied as a single group, which drops every table
+			if err := s.RollbackMigrations(ctx); err != nil {
+				t.Fatalf("RollbackMigrations: %v", err)
+			}
+			if n := appliedMigrations(t, s); n != 0 {
+				t.Fatalf("%d migrations applied after the rollback, want 0", n)
+			}
+			if _, err := s.DB.ExecContext(ctx, "SELECT 1 FROM orders"); err == nil {
+				t.Fatal("orders table left after the rollback")
+			}
+
+			// The downs leave a database the ups apply to again
+			if err := s.Migrate(ctx); err != nil {
+				t.Fatalf("Migrate after the rollback: %v", err)
+			}
+
+			o := newTestListing(1, 100, time.Now().Unix()+3600)
+			if err := s.PutOrder(ctx, o); err != nil {
+				t.Fatalf("PutOrder: %v", err)
+			}
+			if _, err := s.GetOrder(ctx, o.ChainID, o.Hash); err != nil {
+				t.Fatalf("GetOrder: %v", err)
+			}
+		})
+	}
+}
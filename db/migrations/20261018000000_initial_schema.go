@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// Creates the order, event and checkpoint tables. Hashes, addresses and uint256 values are stored as
+// big-endian bytea, so that byte order matches numeric order.
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return exec(ctx, db,
+			`CREATE TABLE orders (
+				chain_id BIGINT NOT NULL,
+				hash BYTEA NOT NULL,
+				seaport BYTEA NOT NULL,
+				seaport_version VARCHAR NOT NULL,
+				offerer BYTEA NOT NULL,
+				zone BYTEA NOT NULL,
+				order_type SMALLINT NOT NULL,
+				start_time BYTEA NOT NULL,
+				end_time BYTEA NOT NULL,
+				zone_hash BYTEA NOT NULL,
+				salt BYTEA NOT NULL,
+				conduit_key BYTEA NOT NULL,
+				counter BYTEA NOT NULL,
+				total_original_consideration_items INTEGER NOT NULL,
+				signature BYTEA NOT NULL,
+				status VARCHAR NOT NULL,
+				is_validated BOOLEAN NOT NULL,
+				total_filled BYTEA NOT NULL,
+				total_size BYTEA NOT NULL,
+				validated_at TIMESTAMP,
+				filled_at TIMESTAMP,
+				side SMALLINT NOT NULL,
+				collection BYTEA NOT NULL,
+				price BYTEA NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+				PRIMARY KEY (chain_id, hash)
+			)`,
+			`CREATE INDEX orders_collection_idx ON orders (chain_id, collection, side, status, price)`,
+			`CREATE INDEX orders_offerer_idx ON orders (chain_id, offerer, status)`,
+			`CREATE INDEX orders_status_idx ON orders (status, created_at)`,
+
+			`CREATE TABLE offer_items (
+				chain_id BIGINT NOT NULL,
+				order_hash BYTEA NOT NULL,
+				item_index INTEGER NOT NULL,
+				item_type SMALLINT NOT NULL,
+				token BYTEA NOT NULL,
+				identifier_or_criteria BYTEA NOT NULL,
+				start_amount BYTEA NOT NULL,
+				end_amount BYTEA NOT NULL,
+				PRIMARY KEY (chain_id, order_hash, item_index)
+			)`,
+			`CREATE INDEX offer_items_token_idx ON offer_items (chain_id, token)`,
+
+			`CREATE TABLE consideration_items (
+				chain_id BIGINT NOT NULL,
+				order_hash BYTEA NOT NULL,
+				item_index INTEGER NOT NULL,
+				item_type SMALLINT NOT NULL,
+				token BYTEA NOT NULL,
+				identifier_or_criteria BYTEA NOT NULL,
+				start_amount BYTEA NOT NULL,
+				end_amount BYTEA NOT NULL,
+				recipient BYTEA NOT NULL,
+				PRIMARY KEY (chain_id, order_hash, item_index)
+			)`,
+
+			`CREATE TABLE fulfilled_orders (
+				`+eventLogColumns+`,
+				hash BYTEA NOT NULL,
+				offerer BYTEA NOT NULL,
+				zone BYTEA NOT NULL,
+				recipient BYTEA NOT NULL,
+				offer JSONB NOT NULL,
+				consideration JSONB NOT NULL,
+				PRIMARY KEY (chain_id, tx_hash, log_index)
+			)`,
+			`CREATE TABLE cancelled_orders (
+				`+eventLogColumns+`,
+				hash BYTEA NOT NULL,
+				offerer BYTEA NOT NULL,
+				zone BYTEA NOT NULL,
+				raw JSONB NOT NULL,
+				PRIMARY KEY (chain_id, tx_hash, log_index)
+			)`,
+			`CREATE TABLE validated_orders (
+				`+eventLogColumns+`,
+				hash BYTEA NOT NULL,
+				offerer BYTEA NOT NULL,
+				zone BYTEA NOT NULL,
+				raw JSONB NOT NULL,
+				PRIMARY KEY (chain_id, tx_hash, log_index)
+			)`,
+			`CREATE TABLE counter_incremented (
+				`+eventLogColumns+`,
+				counter NUMERIC NOT NULL,
+				offerer BYTEA NOT NULL,
+				raw JSONB NOT NULL,
+				PRIMARY KEY (chain_id, tx_hash, log_index)
+			)`,
+			`CREATE INDEX fulfilled_orders_block_idx ON fulfilled_orders (chain_id, block_number, block_hash)`,
+			`CREATE INDEX fulfilled_orders_hash_idx ON fulfilled_orders (chain_id, hash)`,
+			`CREATE INDEX cancelled_orders_block_idx ON cancelled_orders (chain_id, block_number, block_hash)`,
+			`CREATE INDEX cancelled_orders_hash_idx ON cancelled_orders (chain_id, hash)`,
+			`CREATE INDEX validated_orders_block_idx ON validated_orders (chain_id, block_number, block_hash)`,
+			`CREATE INDEX validated_orders_hash_idx ON validated_orders (chain_id, hash)`,
+			`CREATE INDEX counter_incremented_block_idx ON counter_incremented (chain_id, block_number, block_hash)`,
+			`CREATE INDEX counter_incremented_offerer_idx ON counter_incremented (chain_id, offerer)`,
+
+			`CREATE TABLE checkpoints (
+				name VARCHAR NOT NULL,
+				block_number BIGINT NOT NULL,
+				updated_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (name)
+			)`,
+		)
+	}, func(ctx context.Context, db *bun.DB) error {
+		return exec(ctx, db,
+			`DROP TABLE IF EXISTS checkpoints`,
+			`DROP TABLE IF EXISTS counter_incremented`,
+			`DROP TABLE IF EXISTS validated_orders`,
+			`DROP TABLE IF EXISTS cancelled_orders`,
+			`DROP TABLE IF EXISTS fulfilled_orders`,
+			`DROP TABLE IF EXISTS consideration_items`,
+			`DROP TABLE IF EXISTS offer_items`,
+			`DROP TABLE IF EXISTS orders`,
+		)
+	})
+}
+
+// Columns of db.EventLog shared by the event tables
+const eventLogColumns = `chain_id BIGINT NOT NULL,
+				seaport BYTEA NOT NULL,
+				seaport_version VARCHAR NOT NULL,
+				block_number BIGINT NOT NULL,
+				block_hash BYTEA NOT NULL,
+				tx_hash BYTEA NOT NULL,
+				log_index INTEGER NOT NULL`
@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/uptrace/bun"
+)
+
+// Tables of the Seaport events
+var eventTables = []string{"fulfilled_orders", "cancelled_orders", "validated_orders", "counter_incremented"}
+
+// Adds the block timestamp and transaction sender to the event tables, drops the raw log JSON the
+// columns replace and stores counters as 32 byte big-endian bytea like the other uint256 values
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			queries := []string{}
+
+			for _, t := range eventTables {
+				queries = append(queries,
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN block_timestamp TIMESTAMP`, t),
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN tx_sender BYTEA`, t),
+					fmt.Sprintf(`CREATE INDEX %s_timestamp_idx ON %s (chain_id, block_timestamp)`, t, t),
+					fmt.Sprintf(`CREATE INDEX %s_sender_idx ON %s (chain_id, tx_sender)`, t, t),
+				)
+
+				if t != "fulfilled_orders" {
+					queries = append(queries, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN raw`, t))
+				}
+			}
+
+			queries = append(queries, `ALTER TABLE counter_incremented ADD COLUMN counter_bytes BYTEA`)
+
+			if err := execTx(ctx, tx, queries...); err != nil {
+				return err
+			}
+
+			err := convertCounters(ctx, tx, "CAST(counter AS VARCHAR)", "counter_bytes", func(b []byte) (interface{}, error) {
+				s := string(b)
+
+				// SQLite stored counters above 2^63 as floats, they are rounded to the nearest integer
+				x, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+				if err != nil {
+					return nil, err
+				}
+
+				n, _ := x.Int(nil)
+				if n.Sign() < 0 || n.BitLen() > 256 {
+					return nil, fmt.Errorf("counter %s out of uint256 range", s)
+				}
+
+				return math.U256Bytes(n), nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return execTx(ctx, tx,
+				`ALTER TABLE counter_incremented DROP COLUMN counter`,
+				`ALTER TABLE counter_incremented RENAME COLUMN counter_bytes TO counter`,
+			)
+		})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if _, err := tx.ExecContext(ctx, `ALTER TABLE counter_incremented ADD COLUMN counter_numeric NUMERIC`); err != nil {
+				return err
+			}
+
+			err := convertCounters(ctx, tx, "counter", "counter_numeric", func(b []byte) (interface{}, error) {
+				return new(big.Int).SetBytes(b).String(), nil
+			})
+			if err != nil {
+				return err
+			}
+
+			queries := []string{
+				`ALTER TABLE counter_incremented DROP COLUMN counter`,
+				`ALTER TABLE counter_incremented RENAME COLUMN counter_numeric TO counter`,
+			}
+
+			// The raw logs are gone, rows written since the migration get an empty object
+			for _, t := range eventTables {
+				queries = append(queries,
+					fmt.Sprintf(`DROP INDEX %s_timestamp_idx`, t),
+					fmt.Sprintf(`DROP INDEX %s_sender_idx`, t),
+					fmt.Sprintf(`ALTER TABLE %s DROP COLUMN block_timestamp`, t),
+					fmt.Sprintf(`ALTER TABLE %s DROP COLUMN tx_sender`, t),
+				)
+
+				if t != "fulfilled_orders" {
+					queries = append(queries, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN raw JSONB NOT NULL DEFAULT '{}'`, t))
+				}
+			}
+
+			return execTx(ctx, tx, queries...)
+		})
+	})
+}
+
+// Rewrites the counter of every counter_incremented row, from is the expression the current counter
+// is selected with and to the column the converted counter is written to
+func convertCounters(ctx context.Context, tx bun.Tx, from string, to string, convert func([]byte) (interface{}, error)) error {
+	var rows []struct {
+		ChainID  int64
+		TxHash   []byte
+		LogIndex int64
+		Counter  []byte
+	}
+
+	err := tx.NewSelect().
+		TableExpr("counter_incremented").
+		ColumnExpr("chain_id, tx_hash, log_index").
+		ColumnExpr(from+" AS counter").
+		Scan(ctx, &rows)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		v, err := convert(r.Counter)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			TableExpr("counter_incremented").
+			Set("? = ?", bun.Ident(to), v).
+			Where("chain_id = ?", r.ChainID).
+			Where("tx_hash = ?", r.TxHash).
+			Where("log_index = ?", r.LogIndex).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
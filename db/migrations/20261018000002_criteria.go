@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// Adds the token ids of the criteria merkle roots, served to peers with the Criteria message
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return exec(ctx, db,
+			`CREATE TABLE criteria (
+				root BYTEA NOT NULL,
+				token_ids JSONB NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+				PRIMARY KEY (root)
+			)`,
+		)
+	}, func(ctx context.Context, db *bun.DB) error {
+		return exec(ctx, db,
+			`DROP TABLE IF EXISTS criteria`,
+		)
+	})
+}
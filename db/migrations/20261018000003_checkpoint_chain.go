@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Adds the chain id to the checkpoints, so the events of one chain only hold back its own scanners.
+// The backfill checkpoints are named backfill:<chain id>:<address>, their chain is taken from the name.
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return rebuildCheckpoints(ctx, tx,
+				`CREATE TABLE checkpoints_new (
+					chain_id BIGINT NOT NULL,
+					name VARCHAR NOT NULL,
+					block_number BIGINT NOT NULL,
+					updated_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (chain_id, name)
+				)`,
+				func(name string) int64 {
+					parts := strings.SplitN(name, ":", 3)
+					if len(parts) != 3 || parts[0] != "backfill" {
+						return 0
+					}
+
+					id, err := strconv.ParseInt(parts[1], 10, 64)
+					if err != nil {
+						return 0
+					}
+
+					return id
+				})
+		})
+	}, func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return rebuildCheckpoints(ctx, tx,
+				`CREATE TABLE checkpoints_new (
+					name VARCHAR NOT NULL,
+					block_number BIGINT NOT NULL,
+					updated_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (name)
+				)`,
+				nil)
+		})
+	})
+}
+
+// Copies the checkpoints to a new table that replaces the old one. The chain id of every row is set
+// with chainID, or left out if it is nil.
+func rebuildCheckpoints(ctx context.Context, tx bun.Tx, create string, chainID func(name string) int64) error {
+	var rows []struct {
+		Name        string
+		BlockNumber int64
+		UpdatedAt   time.Time
+	}
+
+	err := tx.NewSelect().
+		TableExpr("checkpoints").
+		ColumnExpr("name, block_number, updated_at").
+		Scan(ctx, &rows)
+	if err != nil {
+		return err
+	}
+
+	if err := execTx(ctx, tx, create); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		values := map[string]interface{}{
+			"name":         r.Name,
+			"block_number": r.BlockNumber,
+			"updated_at":   r.UpdatedAt,
+		}
+		if chainID != nil {
+			values["chain_id"] = chainID(r.Name)
+		}
+
+		_, err := tx.NewInsert().Model(&values).TableExpr("checkpoints_new").Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return execTx(ctx, tx,
+		`DROP TABLE checkpoints`,
+		`ALTER TABLE checkpoints_new RENAME TO checkpoints`,
+	)
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+// Versioned schema migrations, applied in the order of the timestamp their file name starts with.
+// Applied migrations must never be edited, schema changes go in a new migration with its own down.
+var Migrations = migrate.NewMigrations()
+
+// Runs the statements of a migration in a single transaction
+func exec(ctx context.Context, db *bun.DB, queries ...string) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return execTx(ctx, tx, queries...)
+	})
+}
+
+// Runs statements in a transaction the migration already started
+func execTx(ctx context.Context, tx bun.Tx, queries ...string) error {
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
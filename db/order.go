@@ -0,0 +1,470 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/uptrace/bun"
+)
+
+var ErrOrderNotFound = errors.New("order not found")
+
+// Side of the order book an order is on
+type OrderSide uint8
+
+const (
+	// Listings, orders offering NFTs
+	SellSide OrderSide = iota
+	// Offers, orders offering fungible tokens for NFTs
+	BuySide
+)
+
+// Sort order of order queries
+type OrderSort uint8
+
+const (
+	SortNewest OrderSort = iota
+	SortOldest
+	SortEndingSoon
+	SortPriceLowToHigh
+	SortPriceHighToLow
+	SortRecentlyFulfilled
+	SortRecentlyValidated
+)
+
+// Filters and pagination of an order query, zero filters match every order
+type OrderQuery struct {
+	// Chain the orders are on, all chains if zero
+	ChainID    int64
+	Collection common.Address
+	Offerer    common.Address
+	// Active orders past their end time or before their start time are left out, they are only
+	// marked expired when revalidated
+	Status string
+	// Listings and offers if nil
+	Side *OrderSide
+
+	// Orders with an item of the collection with this identifier, or with an item of this type
+	TokenID  *big.Int
+	ItemType *uint8
+
+	// Inclusive price range, unbounded if nil
+	MinPrice *big.Int
+	MaxPrice *big.Int
+
+	Sort   OrderSort
+	Limit  int
+	Offset int
+
+	// Continue after this order of a previous page
+	After *OrderCursor
+}
+
+// Position of an order in the results of a query
+type OrderCursor struct {
+	ChainID int64
+	Hash    common.Hash
+}
+
+// Returns the stored column an order sort is sorted by and whether it sorts in descending order.
+// Orders with the same value are sorted by chain id and hash in the same direction.
+func (s OrderSort) key() (string, bool) {
+	switch s {
+	case SortOldest:
+		return "created_at", false
+	case SortEndingSoon:
+		return "end_time", false
+	case SortPriceLowToHigh:
+		return "price", false
+	case SortPriceHighToLow:
+		return "price", true
+	case SortRecentlyFulfilled:
+		return "filled_at", true
+	case SortRecentlyValidated:
+		return "validated_at", true
+	}
+
+	return "created_at", true
+}
+
+// Creates a new Order for the deployment of the domain from its parameters, deriving the side,
+// collection and price from its items
+func NewOrder(d order.Domain, hash common.Hash, params abi.OrderParameters, counter *big.Int, signature []byte) *Order {
+	o := &Order{
+		Hash:                            hash,
+		ChainID:                         d.ChainID.Int64(),
+		Seaport:                         d.VerifyingContract,
+		SeaportVersion:                  d.Version,
+		Offerer:                         params.Offerer,
+		Zone:                            params.Zone,
+		OrderType:                       params.OrderType,
+		StartTime:                       NewUint256(params.StartTime),
+		EndTime:                         NewUint256(params.EndTime),
+		ZoneHash:                        params.ZoneHash,
+		Salt:                            NewUint256(params.Salt),
+		ConduitKey:                      params.ConduitKey,
+		Counter:                         NewUint256(counter),
+		TotalOriginalConsiderationItems: int(params.TotalOriginalConsiderationItems.Int64()),
+		Signature:                       signature,
+		Status:                          StatusActive,
+		TotalFilled:                     new(Uint256),
+		TotalSize:                       new(Uint256),
+		Side:                            BuySide,
+	}
+
+	for i, item := range params.Offer {
+		o.Offer = append(o.Offer, &OfferItem{
+			ChainID:              o.ChainID,
+			OrderHash:            hash,
+			ItemIndex:            i,
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: NewUint256(item.IdentifierOrCriteria),
+			StartAmount:          NewUint256(item.StartAmount),
+			EndAmount:            NewUint256(item.EndAmount),
+		})
+
+		if abi.IsNFT(item.ItemType) && o.Side != SellSide {
+			o.Side = SellSide
+			o.Collection = item.Token
+		}
+	}
+
+	for i, item := range params.Consideration {
+		o.Consideration = append(o.Consideration, &ConsiderationItem{
+			ChainID:              o.ChainID,
+			OrderHash:            hash,
+			ItemIndex:            i,
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: NewUint256(item.IdentifierOrCriteria),
+			StartAmount:          NewUint256(item.StartAmount),
+			EndAmount:            NewUint256(item.EndAmount),
+			Recipient:            item.Recipient,
+		})
+
+		if o.Side == BuySide && abi.IsNFT(item.ItemType) && o.Collection == (common.Address{}) {
+			o.Collection = item.Token
+		}
+	}
+
+	// Listings are priced by what the offerer receives, offers by what the offerer pays
+	price := new(big.Int)
+	if o.Side == SellSide {
+		for _, item := range params.Consideration {
+			if !abi.IsNFT(item.ItemType) {
+				price.Add(price, item.StartAmount)
+			}
+		}
+	} else {
+		for _, item := range params.Offer {
+			if !abi.IsNFT(item.ItemType) {
+				price.Add(price, item.StartAmount)
+			}
+		}
+	}
+	o.Price = NewUint256(price)
+
+	return o
+}
+
+// Creates a new Order from the components signed by the offerer
+func NewOrderFromComponents(d order.Domain, hash common.Hash, c abi.OrderComponents, signature []byte) *Order {
+	return NewOrder(d, hash, abi.OrderParameters{
+		Offerer:                         c.Offerer,
+		Zone:                            c.Zone,
+		Offer:                           c.Offer,
+		Consideration:                   c.Consideration,
+		OrderType:                       c.OrderType,
+		StartTime:                       c.StartTime,
+		EndTime:                         c.EndTime,
+		ZoneHash:                        c.ZoneHash,
+		Salt:                            c.Salt,
+		ConduitKey:                      c.ConduitKey,
+		TotalOriginalConsiderationItems: big.NewInt(int64(len(c.Consideration))),
+	}, c.Counter, signature)
+}
+
+// Returns the order parameters as passed to the Seaport contract
+func (o *Order) Parameters() abi.OrderParameters {
+	c := o.Components()
+
+	return abi.OrderParameters{
+		Offerer:                         c.Offerer,
+		Zone:                            c.Zone,
+		Offer:                           c.Offer,
+		Consideration:                   c.Consideration,
+		OrderType:                       c.OrderType,
+		StartTime:                       c.StartTime,
+		EndTime:                         c.EndTime,
+		ZoneHash:                        c.ZoneHash,
+		Salt:                            c.Salt,
+		ConduitKey:                      c.ConduitKey,
+		TotalOriginalConsiderationItems: big.NewInt(int64(o.TotalOriginalConsiderationItems)),
+	}
+}
+
+// Returns the order components signed by the offerer
+func (o *Order) Components() abi.OrderComponents {
+	c := abi.OrderComponents{
+		Offerer:       o.Offerer,
+		Zone:          o.Zone,
+		Offer:         make([]abi.OfferItem, len(o.Offer)),
+		Consideration: make([]abi.ConsiderationItem, len(o.Consideration)),
+		OrderType:     o.OrderType,
+		StartTime:     o.StartTime.Int(),
+		EndTime:       o.EndTime.Int(),
+		ZoneHash:      o.ZoneHash,
+		Salt:          o.Salt.Int(),
+		ConduitKey:    o.ConduitKey,
+		Counter:       o.Counter.Int(),
+	}
+
+	for i, item := range o.Offer {
+		c.Offer[i] = abi.OfferItem{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria.Int(),
+			StartAmount:          item.StartAmount.Int(),
+			EndAmount:            item.EndAmount.Int(),
+		}
+	}
+
+	for i, item := range o.Consideration {
+		c.Consideration[i] = abi.ConsiderationItem{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria.Int(),
+			StartAmount:          item.StartAmount.Int(),
+			EndAmount:            item.EndAmount.Int(),
+			Recipient:            item.Recipient,
+		}
+	}
+
+	return c
+}
+
+// Writes an order and its items in a single transaction, orders already stored are ignored
+func (s *SQLWrapper) PutOrder(ctx context.Context, o *Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewInsert().Model(o).Ignore().Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+
+		if len(o.Offer) > 0 {
+			if _, err := tx.NewInsert().Model(&o.Offer).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		if len(o.Consideration) > 0 {
+			if _, err := tx.NewInsert().Model(&o.Consideration).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Updates the status of a stored order
+func (s *SQLWrapper) UpdateOrderStatus(ctx context.Context, chainID int64, hash common.Hash, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.DB.NewUpdate().
+		Model((*Order)(nil)).
+		Set("status = ?", status).
+		Where("chain_id = ?", chainID).
+		Where("hash = ?", hash).
+		Exec(ctx)
+
+	return err
+}
+
+// Returns the distinct tokens offered by orders on the chain that are re-validated when their tokens move
+func (s *SQLWrapper) OfferTokens(ctx context.Context, chainID int64) ([]common.Address, error) {
+	var tokens []common.Address
+
+	err := s.DB.NewSelect().
+		Model((*OfferItem)(nil)).
+		ColumnExpr("DISTINCT offer_item.token").
+		Join("JOIN orders AS o ON o.chain_id = offer_item.chain_id AND o.hash = offer_item.order_hash").
+		Where("o.chain_id = ?", chainID).
+		Where("o.status IN (?)", bun.In(RevalidatedStatuses)).
+		Scan(ctx, &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Returns the re-validated orders on the chain of the offerers that offer the token
+func (s *SQLWrapper) OrdersByOfferToken(ctx context.Context, chainID int64, offerers []common.Address, token common.Address) ([]*Order, error) {
+	var orders []*Order
+
+	err := s.selectOrders(&orders).
+		Where("o.chain_id = ?", chainID).
+		Where("o.offerer IN (?)", bun.In(offerers)).
+		Where("o.status IN (?)", bun.In(RevalidatedStatuses)).
+		Where("EXISTS (SELECT 1 FROM offer_items AS oi WHERE oi.chain_id = o.chain_id AND oi.order_hash = o.hash AND oi.token = ?)", token).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// Returns the order on the chain with the given hash and its items
+func (s *SQLWrapper) GetOrder(ctx context.Context, chainID int64, hash common.Hash) (*Order, error) {
+	o := new(Order)
+
+	err := s.selectOrders(o).Where("o.chain_id = ?", chainID).Where("o.hash = ?", hash).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Returns the orders matching the query and their items
+func (s *SQLWrapper) QueryOrders(ctx context.Context, q OrderQuery) ([]*Order, error) {
+	var orders []*Order
+
+	err := s.filterOrders(s.selectOrders(&orders), q).Limit(q.Limit).Offset(q.Offset).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// Returns the hashes of the orders matching the query
+func (s *SQLWrapper) QueryOrderHashes(ctx context.Context, q OrderQuery) ([]common.Hash, error) {
+	var hashes []common.Hash
+
+	err := s.filterOrders(s.DB.NewSelect().Model((*Order)(nil)).Column("o.hash"), q).
+		Limit(q.Limit).
+		Offset(q.Offset).
+		Scan(ctx, &hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// Returns the number of orders matching the query, ignoring its pagination
+func (s *SQLWrapper) CountOrders(ctx context.Context, q OrderQuery) (int, error) {
+	return s.filterOrders(s.DB.NewSelect().Model((*Order)(nil)), q).Count(ctx)
+}
+
+func (s *SQLWrapper) selectOrders(model interface{}) *bun.SelectQuery {
+	return selectOrders(s.DB, model)
+}
+
+// Selects orders with their items, in a transaction or not
+func selectOrders(db bun.IDB, model interface{}) *bun.SelectQuery {
+	return db.NewSelect().
+		Model(model).
+		Relation("Offer", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("item_index")
+		}).
+		Relation("Consideration", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("item_index")
+		})
+}
+
+func (s *SQLWrapper) filterOrders(sq *bun.SelectQuery, q OrderQuery) *bun.SelectQuery {
+	if q.Side != nil {
+		sq = sq.Where("o.side = ?", *q.Side)
+	}
+
+	if q.ChainID != 0 {
+		sq = sq.Where("o.chain_id = ?", q.ChainID)
+	}
+
+	if q.Collection != (common.Address{}) {
+		sq = sq.Where("o.collection = ?", q.Collection)
+	}
+
+	if q.Offerer != (common.Address{}) {
+		sq = sq.Where("o.offerer = ?", q.Offerer)
+	}
+
+	if q.Status != "" {
+		sq = sq.Where("o.status = ?", q.Status)
+	}
+
+	// Times are 32 byte big-endian, so they compare as numbers
+	if q.Status == StatusActive {
+		now := NewUint256(big.NewInt(time.Now().Unix()))
+		sq = sq.Where("o.start_time <= ?", now).Where("o.end_time > ?", now)
+	}
+
+	if q.TokenID != nil {
+		sq = sq.Where("(EXISTS (SELECT 1 FROM offer_items AS oi WHERE oi.chain_id = o.chain_id AND oi.order_hash = o.hash AND oi.token = o.collection AND oi.identifier_or_criteria = ?0) "+
+			"OR EXISTS (SELECT 1 FROM consideration_items AS ci WHERE ci.chain_id = o.chain_id AND ci.order_hash = o.hash AND ci.token = o.collection AND ci.identifier_or_criteria = ?0))", NewUint256(q.TokenID))
+	}
+
+	if q.ItemType != nil {
+		sq = sq.Where("(EXISTS (SELECT 1 FROM offer_items AS oi WHERE oi.chain_id = o.chain_id AND oi.order_hash = o.hash AND oi.item_type = ?0) "+
+			"OR EXISTS (SELECT 1 FROM consideration_items AS ci WHERE ci.chain_id = o.chain_id AND ci.order_hash = o.hash AND ci.item_type = ?0))", *q.ItemType)
+	}
+
+	// Prices are 32 byte big-endian, so they compare as numbers
+	if q.MinPrice != nil {
+		sq = sq.Where("o.price >= ?", NewUint256(q.MinPrice))
+	}
+
+	if q.MaxPrice != nil {
+		sq = sq.Where("o.price <= ?", NewUint256(q.MaxPrice))
+	}
+
+	switch q.Sort {
+	case SortRecentlyFulfilled:
+		sq = sq.Where("o.filled_at IS NOT NULL")
+	case SortRecentlyValidated:
+		sq = sq.Where("o.validated_at IS NOT NULL")
+	}
+
+	col, desc := q.Sort.key()
+	dir, cmp := "ASC", ">"
+	if desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	// The cursor order is looked up so that the sort values are compared as they are stored
+	if q.After != nil {
+		cursor := fmt.Sprintf("(SELECT c.%s FROM orders AS c WHERE c.chain_id = ?0 AND c.hash = ?1)", col)
+
+		sq = sq.Where(fmt.Sprintf("(o.%[1]s %[2]s %[3]s OR (o.%[1]s = %[3]s AND (o.chain_id %[2]s ?0 OR (o.chain_id = ?0 AND o.hash %[2]s ?1))))", col, cmp, cursor),
+			q.After.ChainID, q.After.Hash)
+	}
+
+	sq = sq.OrderExpr(fmt.Sprintf("o.%s %s", col, dir)).
+		OrderExpr("o.chain_id " + dir).
+		OrderExpr("o.hash " + dir)
+
+	return sq
+}
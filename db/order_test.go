@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestQueryActiveOrders(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+		now := time.Now().Unix()
+
+		live := newTestListing(1, 100, now+3600)
+		expired := newTestListing(2, 100, now-1)
+		upcoming := newTestListing(3, 100, now+3600)
+		upcoming.StartTime = NewUint256(big.NewInt(now + 600))
+
+		for _, o := range []*Order{live, expired, upcoming} {
+			if err := s.PutOrder(ctx, o); err != nil {
+				t.Fatalf("PutOrder: %v", err)
+			}
+		}
+
+		q := OrderQuery{ChainID: 1, Status: StatusActive}
+
+		orders, err := s.QueryOrders(ctx, q)
+		if err != nil {
+			t.Fatalf("QueryOrders: %v", err)
+		}
+
+		if len(orders) != 1 || orders[0].Hash != live.Hash {
+			t.Fatalf("active orders = %d, want only the order that started and ends in an hour", len(orders))
+		}
+
+		if n, err := s.CountOrders(ctx, q); err != nil || n != 1 {
+			t.Fatalf("CountOrders = %d, %v, want 1", n, err)
+		}
+
+		// Expired and upcoming orders are still returned when no status is asked for
+		q.Status = ""
+		if n, err := s.CountOrders(ctx, q); err != nil || n != 3 {
+			t.Fatalf("CountOrders of any status = %d, %v, want 3", n, err)
+		}
+	})
+}
+
+func TestQueryOrdersSide(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+		end := time.Now().Unix() + 3600
+
+		listing := newTestListing(1, 100, end)
+		offer := newTestOffer(1, 90, end)
+
+		for _, o := range []*Order{listing, offer} {
+			if err := s.PutOrder(ctx, o); err != nil {
+				t.Fatalf("PutOrder: %v", err)
+			}
+		}
+
+		sell, buy := SellSide, BuySide
+
+		cases := []struct {
+			name string
+			side *OrderSide
+			want []*Order
+		}{
+			{"any side", nil, []*Order{listing, offer}},
+			{"listings", &sell, []*Order{listing}},
+			{"offers", &buy, []*Order{offer}},
+		}
+
+		for _, tc := range cases {
+			orders, err := s.QueryOrders(ctx, OrderQuery{ChainID: 1, Side: tc.side, Sort: SortPriceHighToLow})
+			if err != nil {
+				t.Fatalf("%s: QueryOrders: %v", tc.name, err)
+			}
+
+			if len(orders) != len(tc.want) {
+				t.Fatalf("%s: got %d orders, want %d", tc.name, len(orders), len(tc.want))
+			}
+			for i, o := range orders {
+				if o.Hash != tc.want[i].Hash {
+					t.Fatalf("%s: order %d is %s, want %s", tc.name, i, o.Hash.Hex(), tc.want[i].Hash.Hex())
+				}
+			}
+		}
+	})
+}
+
+func TestPutOrderRoundTrip(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		ctx := context.Background()
+
+		weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+		c := abi.OrderComponents{
+			Offerer: testOfferer,
+			Zone:    common.HexToAddress("0x000000000000000000000000000000000000000a"),
+			Offer: []abi.OfferItem{
+				{ItemType: abi.ItemTypeERC721, Token: testCollection, IdentifierOrCriteria: big.NewInt(1), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+				{ItemType: abi.ItemTypeERC1155, Token: testCollection, IdentifierOrCriteria: big.NewInt(2), StartAmount: big.NewInt(5), EndAmount: big.NewInt(3)},
+			},
+			Consideration: []abi.ConsiderationItem{
+				{ItemType: abi.ItemTypeERC20, Token: weth, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(950), EndAmount: big.NewInt(950), Recipient: testOfferer},
+				{ItemType: abi.ItemTypeERC20, Token: weth, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(50), EndAmount: big.NewInt(50), Recipient: common.HexToAddress("0x000000000000000000000000000000000000fee0")},
+			},
+			OrderType:  abi.OrderTypePartialRestricted,
+			StartTime:  big.NewInt(1),
+			EndTime:    big.NewInt(time.Now().Unix() + 3600),
+			ZoneHash:   common.HexToHash("0x01"),
+			Salt:       new(big.Int).Lsh(big.NewInt(1), 255),
+			ConduitKey: common.HexToHash("0x02"),
+			Counter:    big.NewInt(3),
+		}
+
+		hash := order.Hash(&c)
+		if err := s.PutOrder(ctx, NewOrderFromComponents(testDomain, hash, c, []byte{1, 2, 3})); err != nil {
+			t.Fatalf("PutOrder: %v", err)
+		}
+
+		// Storing an order again is ignored
+		if err := s.PutOrder(ctx, NewOrderFromComponents(testDomain, hash, c, []byte{1, 2, 3})); err != nil {
+			t.Fatalf("PutOrder of a stored order: %v", err)
+		}
+
+		got, err := s.GetOrder(ctx, 1, hash)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+
+		// The stored items give back the order hash the offerer signed
+		components := got.Components()
+		if h := order.Hash(&components); h != hash {
+			t.Fatalf("stored order hashes to %s, want %s", h.Hex(), hash.Hex())
+		}
+
+		if got.Side != SellSide || got.Collection != testCollection || got.Price.Int().Int64() != 1000 {
+			t.Fatalf("stored order on side %d of %s priced %s, want a listing of %s priced 1000", got.Side, got.Collection.Hex(), got.Price.Int(), testCollection.Hex())
+		}
+
+		if string(got.Signature) != string([]byte{1, 2, 3}) || got.Seaport != testDomain.VerifyingContract || got.TotalOriginalConsiderationItems != 2 {
+			t.Fatalf("stored order %+v lost its signature, deployment or consideration count", got)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"goport/order"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/uptrace/bun"
+)
+
+// Event models whose rows are rolled back with their block
+var eventModels = []interface{}{
+	(*FulfilledOrder)(nil),
+	(*CancelledOrder)(nil),
+	(*ValidatedOrder)(nil),
+	(*CounterIncremented)(nil),
+}
+
+// Orders and offerers whose state depended on rolled back events
+type Rollback struct {
+	Orders   []common.Hash
+	Offerers []common.Address
+}
+
+// Returns true if nothing was rolled back
+func (r *Rollback) Empty() bool {
+	return len(r.Orders) == 0 && len(r.Offerers) == 0
+}
+
+// Returns the hashes of the blocks on the chain with persisted events, by block number, starting at the given block
+func (s *SQLWrapper) EventBlocks(ctx context.Context, chainID int64, from uint64) (map[uint64][]common.Hash, error) {
+	blocks := make(map[uint64][]common.Hash)
+	seen := make(map[common.Hash]bool)
+
+	for _, model := range eventModels {
+		var rows []EventLog
+
+		err := s.DB.NewSelect().
+			Model(model).
+			Distinct().
+			Column("block_number", "block_hash").
+			Where("chain_id = ?", chainID).
+			Where("block_number >= ?", from).
+			Scan(ctx, &rows)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			if !seen[r.BlockHash] {
+				seen[r.BlockHash] = true
+				blocks[r.BlockNumber] = append(blocks[r.BlockNumber], r.BlockHash)
+			}
+		}
+	}
+
+	return blocks, nil
+}
+
+// Deletes the events of a block that is no longer part of the canonical chain and returns the
+// orders and offerers they affected, which have to be re-validated
+func (s *SQLWrapper) RollbackBlock(ctx context.Context, chainID int64, blockHash common.Hash) (*Rollback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := &Rollback{}
+
+	err := s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, model := range eventModels[:3] {
+			var hashes []common.Hash
+
+			_, err := tx.NewDelete().
+				Model(model).
+				Where("chain_id = ?", chainID).
+				Where("block_hash = ?", blockHash).
+				Returning("hash").
+				Exec(ctx, &hashes)
+			if err != nil {
+				return err
+			}
+
+			for _, h := range hashes {
+				if !containsHash(r.Orders, h) {
+					r.Orders = append(r.Orders, h)
+				}
+			}
+		}
+
+		var offerers []common.Address
+
+		_, err := tx.NewDelete().
+			Model((*CounterIncremented)(nil)).
+			Where("chain_id = ?", chainID).
+			Where("block_hash = ?", blockHash).
+			Returning("offerer").
+			Exec(ctx, &offerers)
+		if err != nil {
+			return err
+		}
+
+		r.Offerers = offerers
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Returns the orders on the chain of the offerers that were flagged by a counter increment
+func (s *SQLWrapper) StaleOrders(ctx context.Context, chainID int64, offerers []common.Address) ([]*Order, error) {
+	var orders []*Order
+
+	err := s.selectOrders(&orders).
+		Where("o.chain_id = ?", chainID).
+		Where("o.offerer IN (?)", bun.In(offerers)).
+		Where("o.status = ?", StatusStaleCounter).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// Replaces the state of an order derived from contract events with the result of re-validating it
+func (s *SQLWrapper) UpdateOrderValidation(ctx context.Context, chainID int64, hash common.Hash, res *order.ValidationResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.DB.NewUpdate().
+		Model((*Order)(nil)).
+		Set("status = ?", StatusFromResult(res)).
+		Set("is_validated = ?", res.IsValidated).
+		Set("total_filled = ?", NewUint256(res.TotalFilled)).
+		Set("total_size = ?", NewUint256(res.TotalSize)).
+		Where("chain_id = ?", chainID).
+		Where("hash = ?", hash)
+
+	if !res.IsValidated {
+		q = q.Set("validated_at = NULL")
+	}
+
+	if res.TotalFilled.Sign() == 0 {
+		q = q.Set("filled_at = NULL")
+	}
+
+	_, err := q.Exec(ctx)
+
+	return err
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
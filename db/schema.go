@@ -1,38 +1,168 @@
 package db
 
 import (
-	"goport/abi"
-	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/uptrace/bun"
 )
 
+// Position of a persisted event on the chain, used to roll it back when its block is reorged out,
+// the Seaport deployment that emitted it and the transaction it was emitted by. A log is identified
+// by its chain, transaction and index.
+type EventLog struct {
+	ChainID        int64          `bun:",pk"`
+	Seaport        common.Address `bun:"type:bytea,notnull"`
+	SeaportVersion string         `bun:",notnull"`
+	BlockNumber    uint64         `bun:",notnull"`
+	BlockHash      common.Hash    `bun:"type:bytea,notnull"`
+	TxHash         common.Hash    `bun:"type:bytea,pk"`
+	LogIndex       uint           `bun:",pk"`
+
+	// Zero when they could not be fetched from the chain
+	BlockTimestamp time.Time      `bun:",nullzero"`
+	TxSender       common.Address `bun:"type:bytea"`
+}
+
+// Creates a new EventLog from the log an event was decoded from and the deployment that emitted it
+func NewEventLog(e *Event) EventLog {
+	l := e.Log()
+
+	return EventLog{
+		ChainID:        e.Domain.ChainID.Int64(),
+		Seaport:        l.Address,
+		SeaportVersion: e.Domain.Version,
+		BlockNumber:    l.BlockNumber,
+		BlockHash:      l.BlockHash,
+		TxHash:         l.TxHash,
+		LogIndex:       l.Index,
+		BlockTimestamp: e.BlockTimestamp,
+		TxSender:       e.TxSender,
+	}
+}
+
+// Item spent by the fulfiller or offerer of a fulfilled order
+type SpentItem struct {
+	ItemType   uint8          `json:"itemType"`
+	Token      common.Address `json:"token"`
+	Identifier *Uint256       `json:"identifier"`
+	Amount     *Uint256       `json:"amount"`
+}
+
+// Item received by a recipient of a fulfilled order
+type ReceivedItem struct {
+	ItemType   uint8          `json:"itemType"`
+	Token      common.Address `json:"token"`
+	Identifier *Uint256       `json:"identifier"`
+	Amount     *Uint256       `json:"amount"`
+	Recipient  common.Address `json:"recipient"`
+}
+
 type FulfilledOrder struct {
-	Hash          [32]byte           `bun:"type:bytea,notnull"`
-	Offerer       common.Address     `bun:"type:bytea,notnull"`
-	Zone          common.Address     `bun:"type:bytea,notnull"`
-	Recipient     common.Address     `bun:"type:bytea,notnull"`
-	Offer         []abi.SpentItem    `bun:"type:jsonb,notnull"`
-	Consideration []abi.ReceivedItem `bun:"type:jsonb,notnull"`
+	bun.BaseModel `bun:"table:fulfilled_orders"`
+
+	EventLog
+	Hash          common.Hash     `bun:"type:bytea,notnull"`
+	Offerer       common.Address  `bun:"type:bytea,notnull"`
+	Zone          common.Address  `bun:"type:bytea,notnull"`
+	Recipient     common.Address  `bun:"type:bytea,notnull"`
+	Offer         []*SpentItem    `bun:"type:jsonb,notnull"`
+	Consideration []*ReceivedItem `bun:"type:jsonb,notnull"`
 }
 
 type CancelledOrder struct {
-	Hash    [32]byte       `bun:"type:bytea,notnull"`
+	bun.BaseModel `bun:"table:cancelled_orders"`
+
+	EventLog
+	Hash    common.Hash    `bun:"type:bytea,notnull"`
 	Offerer common.Address `bun:"type:bytea,notnull"`
 	Zone    common.Address `bun:"type:bytea,notnull"`
-	Raw     types.Log      `bun:"type:jsonb,notnull"`
 }
 
 type ValidatedOrder struct {
-	Hash    [32]byte       `bun:"type:bytea,notnull"`
+	bun.BaseModel `bun:"table:validated_orders"`
+
+	EventLog
+	Hash    common.Hash    `bun:"type:bytea,notnull"`
 	Offerer common.Address `bun:"type:bytea,notnull"`
 	Zone    common.Address `bun:"type:bytea,notnull"`
-	Raw     types.Log      `bun:"type:jsonb,notnull"`
 }
 
 type CounterIncremented struct {
-	Counter *big.Int       `bun:"type:numeric,notnull"`
+	bun.BaseModel `bun:"table:counter_incremented"`
+
+	EventLog
+	Counter *Uint256       `bun:"type:bytea,notnull"`
 	Offerer common.Address `bun:"type:bytea,notnull"`
-	Raw     types.Log      `bun:"type:jsonb,notnull"`
+}
+
+// Signed Seaport order received from the network or submitted to the node
+type Order struct {
+	bun.BaseModel `bun:"table:orders,alias:o"`
+
+	ChainID                         int64          `bun:",pk"`
+	Hash                            common.Hash    `bun:"type:bytea,pk"`
+	Seaport                         common.Address `bun:"type:bytea,notnull"`
+	SeaportVersion                  string         `bun:",notnull"`
+	Offerer                         common.Address `bun:"type:bytea,notnull"`
+	Zone                            common.Address `bun:"type:bytea,notnull"`
+	OrderType                       uint8          `bun:",notnull"`
+	StartTime                       *Uint256       `bun:"type:bytea,notnull"`
+	EndTime                         *Uint256       `bun:"type:bytea,notnull"`
+	ZoneHash                        common.Hash    `bun:"type:bytea,notnull"`
+	Salt                            *Uint256       `bun:"type:bytea,notnull"`
+	ConduitKey                      common.Hash    `bun:"type:bytea,notnull"`
+	Counter                         *Uint256       `bun:"type:bytea,notnull"`
+	TotalOriginalConsiderationItems int            `bun:",notnull"`
+	Signature                       []byte         `bun:"type:bytea,notnull"`
+
+	// Whether the order can currently be fulfilled, one of the Status constants
+	Status string `bun:",notnull"`
+
+	// State of the order on the Seaport contract, updated from contract events
+	IsValidated bool      `bun:",notnull"`
+	TotalFilled *Uint256  `bun:"type:bytea,notnull"`
+	TotalSize   *Uint256  `bun:"type:bytea,notnull"`
+	ValidatedAt time.Time `bun:",nullzero"`
+	FilledAt    time.Time `bun:",nullzero"`
+
+	// Derived from the order items to make the order queryable
+	Side       OrderSide      `bun:",notnull"`
+	Collection common.Address `bun:"type:bytea,notnull"`
+	Price      *Uint256       `bun:"type:bytea,notnull"`
+	CreatedAt  time.Time      `bun:",nullzero,notnull,default:current_timestamp"`
+
+	Offer         []*OfferItem         `bun:"rel:has-many,join:chain_id=chain_id,join:hash=order_hash"`
+	Consideration []*ConsiderationItem `bun:"rel:has-many,join:chain_id=chain_id,join:hash=order_hash"`
+}
+
+type OfferItem struct {
+	ChainID              int64          `bun:",pk"`
+	OrderHash            common.Hash    `bun:"type:bytea,pk"`
+	ItemIndex            int            `bun:",pk"`
+	ItemType             uint8          `bun:",notnull"`
+	Token                common.Address `bun:"type:bytea,notnull"`
+	IdentifierOrCriteria *Uint256       `bun:"type:bytea,notnull"`
+	StartAmount          *Uint256       `bun:"type:bytea,notnull"`
+	EndAmount            *Uint256       `bun:"type:bytea,notnull"`
+}
+
+type ConsiderationItem struct {
+	ChainID              int64          `bun:",pk"`
+	OrderHash            common.Hash    `bun:"type:bytea,pk"`
+	ItemIndex            int            `bun:",pk"`
+	ItemType             uint8          `bun:",notnull"`
+	Token                common.Address `bun:"type:bytea,notnull"`
+	IdentifierOrCriteria *Uint256       `bun:"type:bytea,notnull"`
+	StartAmount          *Uint256       `bun:"type:bytea,notnull"`
+	EndAmount            *Uint256       `bun:"type:bytea,notnull"`
+	Recipient            common.Address `bun:"type:bytea,notnull"`
+}
+
+// Last block processed by a named block scanner of a chain
+type Checkpoint struct {
+	ChainID     int64     `bun:",pk"`
+	Name        string    `bun:",pk"`
+	BlockNumber uint64    `bun:",notnull"`
+	UpdatedAt   time.Time `bun:",notnull"`
 }
@@ -0,0 +1,44 @@
+package db
+
+import "goport/order"
+
+// Status of a stored order
+const (
+	StatusActive = "active"
+	// The offerer does not own enough of an offer item
+	StatusInvalidBalance = "invalid-balance"
+	// The offerer revoked the approval of the conduit
+	StatusInvalidApproval = "invalid-approval"
+	StatusCancelled       = "cancelled"
+	StatusFilled          = "filled"
+	StatusExpired         = "expired"
+	// The offerer incremented their counter, invalidating every order signed before
+	StatusStaleCounter = "stale-counter"
+	// Any other check failed
+	StatusInvalid = "invalid"
+)
+
+// Orders in these statuses may still become fulfillable and are re-validated when their tokens move
+var RevalidatedStatuses = []string{StatusActive, StatusInvalidBalance, StatusInvalidApproval}
+
+// Returns the status of an order from its validation result, checks the offerer cannot fix take precedence
+func StatusFromResult(res *order.ValidationResult) string {
+	switch {
+	case res.Valid():
+		return StatusActive
+	case res.Has(order.CodeCancelled):
+		return StatusCancelled
+	case res.Has(order.CodeFilled):
+		return StatusFilled
+	case res.Has(order.CodeStaleCounter):
+		return StatusStaleCounter
+	case res.Has(order.CodeExpired):
+		return StatusExpired
+	case res.Fatal():
+		return StatusInvalid
+	case res.Has(order.CodeInvalidBalance):
+		return StatusInvalidBalance
+	}
+
+	return StatusInvalidApproval
+}
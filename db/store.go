@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrUnsupportedEvent = errors.New("unsupported Seaport event")
+
+// Stores signed orders and the state the node derives from them
+type OrderStore interface {
+	// Stores an order and its items, orders already stored are ignored
+	PutOrder(ctx context.Context, o *Order) error
+	// Returns the order on the chain with the given hash, or ErrOrderNotFound
+	GetOrder(ctx context.Context, chainID int64, hash common.Hash) (*Order, error)
+	QueryOrders(ctx context.Context, q OrderQuery) ([]*Order, error)
+	QueryOrderHashes(ctx context.Context, q OrderQuery) ([]common.Hash, error)
+	CountOrders(ctx context.Context, q OrderQuery) (int, error)
+
+	UpdateOrderStatus(ctx context.Context, chainID int64, hash common.Hash, status string) error
+	UpdateOrderValidation(ctx context.Context, chainID int64, hash common.Hash, res *order.ValidationResult) error
+
+	// Lookups used to re-validate orders when their tokens move or their events are rolled back
+	OfferTokens(ctx context.Context, chainID int64) ([]common.Address, error)
+	OrdersByOfferToken(ctx context.Context, chainID int64, offerers []common.Address, token common.Address) ([]*Order, error)
+	StaleOrders(ctx context.Context, chainID int64, offerers []common.Address) ([]*Order, error)
+
+	// Token ids of criteria merkle roots, PutCriteria returns ErrCriteriaMismatch if they do not hash
+	// to the root and GetCriteria returns ErrCriteriaNotFound for unknown roots
+	PutCriteria(ctx context.Context, root common.Hash, tokenIDs []*big.Int) error
+	GetCriteria(ctx context.Context, root common.Hash) ([]*big.Int, error)
+}
+
+// Stores Seaport events, the orders they affect and the progress of block scanners
+type EventStore interface {
+	// Stores the event and applies it to the orders it affects
+	ApplyEvent(ctx context.Context, e *Event) error
+	// Applies the events and writes the checkpoints of a batch at once
+	WriteBatch(ctx context.Context, b *Batch) error
+	QueryEvents(ctx context.Context, q EventQuery) ([]*EventRecord, error)
+	EventBlocks(ctx context.Context, chainID int64, from uint64) (map[uint64][]common.Hash, error)
+	RollbackBlock(ctx context.Context, chainID int64, blockHash common.Hash) (*Rollback, error)
+
+	// Returns the last block of the chain processed by the named scanner, ok is false if it never ran
+	Checkpoint(ctx context.Context, chainID int64, name string) (block uint64, ok bool, err error)
+	PutCheckpoint(ctx context.Context, chainID int64, name string, block uint64) error
+}
+
+// Storage of a goport node
+type Store interface {
+	OrderStore
+	EventStore
+}
+
+// Seaport event emitted by a deployment
+type Event struct {
+	Domain order.Domain
+
+	// One of *abi.SeaportCounterIncremented, *abi.SeaportOrderCancelled, *abi.SeaportOrderValidated
+	// or *abi.SeaportOrderFulfilled
+	Data interface{}
+
+	// Block and transaction metadata, stored with the event
+	BlockTimestamp time.Time
+	TxSender       common.Address
+}
+
+// Events and scanner checkpoints written together
+type Batch struct {
+	Events []*Event
+
+	// Last block processed by each named scanner
+	Checkpoints map[CheckpointKey]uint64
+}
+
+// Named scanner of a chain
+type CheckpointKey struct {
+	ChainID int64
+	Name    string
+}
+
+// Creates a new empty Batch
+func NewBatch() *Batch {
+	return &Batch{Checkpoints: make(map[CheckpointKey]uint64)}
+}
+
+// Returns the number of events and checkpoints in the batch
+func (b *Batch) Len() int {
+	return len(b.Events) + len(b.Checkpoints)
+}
+
+// Returns whether the event data is one of the supported Seaport events
+func (e *Event) Supported() bool {
+	switch e.Data.(type) {
+	case *abi.SeaportCounterIncremented, *abi.SeaportOrderCancelled, *abi.SeaportOrderValidated, *abi.SeaportOrderFulfilled:
+		return true
+	}
+
+	return false
+}
+
+// Returns the timestamp of the block of the event, or the current time if it could not be fetched
+func (e *Event) Time() time.Time {
+	if e.BlockTimestamp.IsZero() {
+		return time.Now().UTC()
+	}
+
+	return e.BlockTimestamp
+}
+
+// Returns the log the event was decoded from
+func (e *Event) Log() types.Log {
+	switch event := e.Data.(type) {
+	case *abi.SeaportCounterIncremented:
+		return event.Raw
+	case *abi.SeaportOrderCancelled:
+		return event.Raw
+	case *abi.SeaportOrderValidated:
+		return event.Raw
+	case *abi.SeaportOrderFulfilled:
+		return event.Raw
+	}
+
+	return types.Log{}
+}
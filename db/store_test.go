@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Opens a migrated database of a dialect, the dialects are run in turn by eachStore
+type testOpener func(t *testing.T) *SQLWrapper
+
+var testDialects = map[string]testOpener{
+	DialectSQLite: func(t *testing.T) *SQLWrapper {
+		s, err := Open(DialectSQLite, filepath.Join(t.TempDir(), "goport.db"))
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+
+		return s
+	},
+}
+
+// Runs a test against a MemoryStore and against every dialect goport was built with
+func eachStore(t *testing.T, test func(t *testing.T, s Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemoryStore())
+	})
+
+	for name, open := range testDialects {
+		open := open
+
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			t.Cleanup(func() { s.DB.Close() })
+
+			if err := s.Migrate(context.Background()); err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+
+			test(t, s)
+		})
+	}
+}
+
+var (
+	testDomain     = order.NewDomain(big.NewInt(1), "1.5", common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"))
+	testCollection = common.HexToAddress("0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e")
+	testOfferer    = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+// Returns a listing of a token of testCollection for price wei, ending at endTime
+func newTestListing(tokenID int64, price int64, endTime int64) *Order {
+	c := abi.OrderComponents{
+		Offerer: testOfferer,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC721, Token: testCollection, IdentifierOrCriteria: big.NewInt(tokenID), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(price), EndAmount: big.NewInt(price), Recipient: testOfferer},
+		},
+		StartTime: big.NewInt(0),
+		EndTime:   big.NewInt(endTime),
+		Salt:      big.NewInt(tokenID),
+		Counter:   new(big.Int),
+	}
+
+	return NewOrderFromComponents(testDomain, order.Hash(&c), c, []byte{1})
+}
+
+// Returns an offer of price wei of an ERC-20 for a token of the test collection
+func newTestOffer(tokenID int64, price int64, endTime int64) *Order {
+	c := abi.OrderComponents{
+		Offerer: testOfferer,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC20, Token: common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(price), EndAmount: big.NewInt(price)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeERC721, Token: testCollection, IdentifierOrCriteria: big.NewInt(tokenID), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1), Recipient: testOfferer},
+		},
+		StartTime: big.NewInt(0),
+		EndTime:   big.NewInt(endTime),
+		Salt:      big.NewInt(1000 + tokenID),
+		Counter:   new(big.Int),
+	}
+
+	return NewOrderFromComponents(testDomain, order.Hash(&c), c, []byte{1})
+}
+
+// Returns the log of a test event, tx tells events apart
+func testLog(tx byte, block uint64) types.Log {
+	return types.Log{
+		Address:     testDomain.VerifyingContract,
+		BlockNumber: block,
+		BlockHash:   common.BigToHash(new(big.Int).SetUint64(block)),
+		TxHash:      common.BytesToHash([]byte{tx}),
+		Index:       uint(tx),
+	}
+}
+
+// Returns a fulfillment of an order at a block with the given timestamp
+func newTestFulfilled(o *Order, tx byte, block uint64, t time.Time) *Event {
+	return &Event{
+		Domain: testDomain,
+		Data: &abi.SeaportOrderFulfilled{
+			OrderHash: o.Hash,
+			Offerer:   o.Offerer,
+			Recipient: common.BytesToAddress([]byte{tx}),
+			Offer:     []abi.SpentItem{},
+			Consideration: []abi.ReceivedItem{
+				{ItemType: abi.ItemTypeNative, Identifier: new(big.Int), Amount: o.Price.Int(), Recipient: o.Offerer},
+			},
+			Raw: testLog(tx, block),
+		},
+		BlockTimestamp: t,
+	}
+}
+
+// Returns the on-chain validation of an order at a block with the given timestamp
+func newTestValidated(o *Order, tx byte, block uint64, t time.Time) *Event {
+	return &Event{
+		Domain: testDomain,
+		Data: &abi.SeaportOrderValidated{
+			OrderHash: o.Hash,
+			Offerer:   o.Offerer,
+			Raw:       testLog(tx, block),
+		},
+		BlockTimestamp: t,
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var ErrUint256Range = errors.New("value out of uint256 range")
+
+// Unsigned 256 bit integer stored as a 32 byte big-endian blob, so that byte order matches numeric order.
+// It is scanned from blobs of at most 32 bytes and from decimal strings, and encoded to JSON as a
+// decimal string so that values above 2^53 survive JSON parsers that use floats.
+type Uint256 big.Int
+
+func NewUint256(x *big.Int) *Uint256 {
+	if x == nil {
+		return new(Uint256)
+	}
+
+	return (*Uint256)(new(big.Int).Set(x))
+}
+
+// Returns the value as a *big.Int
+func (u *Uint256) Int() *big.Int {
+	if u == nil {
+		return new(big.Int)
+	}
+
+	return (*big.Int)(u)
+}
+
+func (u *Uint256) String() string {
+	return u.Int().String()
+}
+
+func (u *Uint256) Value() (driver.Value, error) {
+	x := u.Int()
+	if x.Sign() < 0 || x.BitLen() > 256 {
+		return nil, ErrUint256Range
+	}
+
+	return math.U256Bytes(new(big.Int).Set(x)), nil
+}
+
+func (u *Uint256) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) > 32 {
+			return ErrUint256Range
+		}
+		u.Int().SetBytes(v)
+	case string:
+		return u.setString(v)
+	case int64:
+		if v < 0 {
+			return ErrUint256Range
+		}
+		u.Int().SetInt64(v)
+	case nil:
+		u.Int().SetInt64(0)
+	default:
+		return fmt.Errorf("unsupported uint256 source type %T", src)
+	}
+
+	return nil
+}
+
+func (u *Uint256) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + u.String() + `"`), nil
+}
+
+// Accepts decimal strings and JSON numbers, which older rows were encoded with
+func (u *Uint256) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return u.setString("0")
+	}
+
+	return u.setString(strings.Trim(s, `"`))
+}
+
+func (u *Uint256) setString(s string) error {
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid uint256 %q", s)
+	}
+
+	if x.Sign() < 0 || x.BitLen() > 256 {
+		return ErrUint256Range
+	}
+
+	u.Int().Set(x)
+
+	return nil
+}
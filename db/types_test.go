@@ -0,0 +1,160 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/uptrace/bun"
+)
+
+// Zero, values around the bounds of 64 bit integers and the largest uint256
+var testUint256s = []*big.Int{
+	big.NewInt(0),
+	new(big.Int).Lsh(big.NewInt(1), 63),
+	new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1)),
+	new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+}
+
+func TestUint256Value(t *testing.T) {
+	var last []byte
+
+	for _, x := range testUint256s {
+		v, err := NewUint256(x).Value()
+		if err != nil {
+			t.Fatalf("Value of %s: %v", x, err)
+		}
+
+		b, ok := v.([]byte)
+		if !ok || len(b) != 32 {
+			t.Fatalf("Value of %s = %#v, want 32 bytes", x, v)
+		}
+
+		// Blobs sort like the numbers, the values are in increasing order
+		if last != nil && bytes.Compare(last, b) >= 0 {
+			t.Fatalf("blob of %s does not sort after the previous value", x)
+		}
+		last = b
+
+		var u Uint256
+		if err := u.Scan(b); err != nil {
+			t.Fatalf("Scan of %s: %v", x, err)
+		}
+		if u.Int().Cmp(x) != 0 {
+			t.Fatalf("Scan of the blob of %s = %s", x, u.String())
+		}
+
+		if err := u.Scan(x.String()); err != nil || u.Int().Cmp(x) != 0 {
+			t.Fatalf("Scan of %q = %s, %v", x.String(), u.String(), err)
+		}
+	}
+
+	for _, x := range []*big.Int{big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if _, err := NewUint256(x).Value(); !errors.Is(err, ErrUint256Range) {
+			t.Fatalf("Value of %s: %v, want %v", x, err, ErrUint256Range)
+		}
+	}
+
+	var u Uint256
+	if err := u.Scan(make([]byte, 33)); !errors.Is(err, ErrUint256Range) {
+		t.Fatalf("Scan of 33 bytes: %v, want %v", err, ErrUint256Range)
+	}
+}
+
+func TestUint256JSON(t *testing.T) {
+	for _, x := range testUint256s {
+		b, err := json.Marshal(NewUint256(x))
+		if err != nil {
+			t.Fatalf("Marshal of %s: %v", x, err)
+		}
+		if string(b) != `"`+x.String()+`"` {
+			t.Fatalf("Marshal of %s = %s, want a decimal string", x, b)
+		}
+
+		u := new(Uint256)
+		if err := json.Unmarshal(b, u); err != nil || u.Int().Cmp(x) != 0 {
+			t.Fatalf("Unmarshal of %s = %s, %v", b, u.String(), err)
+		}
+
+		// Older rows encoded the values as JSON numbers
+		u = new(Uint256)
+		if err := json.Unmarshal([]byte(x.String()), u); err != nil || u.Int().Cmp(x) != 0 {
+			t.Fatalf("Unmarshal of the number %s = %s, %v", x, u.String(), err)
+		}
+	}
+}
+
+// Row with a column of each type the schema stores Ethereum values in
+type typesRow struct {
+	bun.BaseModel `bun:"table:types_test"`
+
+	ID            int64           `bun:",pk"`
+	Value         *Uint256        `bun:"type:bytea,notnull"`
+	Address       common.Address  `bun:"type:bytea,notnull"`
+	Hash          common.Hash     `bun:"type:bytea,notnull"`
+	Offer         []*SpentItem    `bun:"type:jsonb,notnull"`
+	Consideration []*ReceivedItem `bun:"type:jsonb,notnull"`
+}
+
+func TestColumnsRoundTrip(t *testing.T) {
+	for name, open := range testDialects {
+		open := open
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			s := open(t)
+			t.Cleanup(func() { s.DB.Close() })
+
+			if _, err := s.DB.NewCreateTable().Model((*typesRow)(nil)).Exec(ctx); err != nil {
+				t.Fatalf("CreateTable: %v", err)
+			}
+
+			for i, x := range testUint256s {
+				row := &typesRow{
+					ID:      int64(i),
+					Value:   NewUint256(x),
+					Address: common.BigToAddress(big.NewInt(int64(i + 1))),
+					Hash:    common.BigToHash(x),
+					Offer: []*SpentItem{
+						{ItemType: 2, Token: testCollection, Identifier: NewUint256(x), Amount: NewUint256(big.NewInt(1))},
+					},
+					Consideration: []*ReceivedItem{
+						{ItemType: 0, Identifier: new(Uint256), Amount: NewUint256(x), Recipient: testOfferer},
+					},
+				}
+
+				if _, err := s.DB.NewInsert().Model(row).Exec(ctx); err != nil {
+					t.Fatalf("Insert of %s: %v", x, err)
+				}
+
+				// Columns are looked up by their stored value
+				got := new(typesRow)
+				err := s.DB.NewSelect().Model(got).
+					Where("value = ?", row.Value).
+					Where("address = ?", row.Address).
+					Where("hash = ?", row.Hash).
+					Scan(ctx)
+				if err != nil {
+					t.Fatalf("Select of %s: %v", x, err)
+				}
+
+				if got.ID != row.ID || got.Value.Int().Cmp(x) != 0 || got.Address != row.Address || got.Hash != row.Hash {
+					t.Fatalf("row of %s = %+v, want %+v", x, got, row)
+				}
+
+				if len(got.Offer) != 1 || got.Offer[0].Token != testCollection || got.Offer[0].Identifier.Int().Cmp(x) != 0 {
+					t.Fatalf("offer of %s = %+v, want %+v", x, got.Offer, row.Offer)
+				}
+
+				if len(got.Consideration) != 1 || got.Consideration[0].Amount.Int().Cmp(x) != 0 || got.Consideration[0].Recipient != testOfferer {
+					t.Fatalf("consideration of %s = %+v, want %+v", x, got.Consideration, row.Consideration)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package db
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Number of times a batch is written before its events are written one by one
+const maxWriteAttempts = 10
+
+// Backoff between attempts to write a batch
+var (
+	minWriteBackoff = 100 * time.Millisecond
+	maxWriteBackoff = 5 * time.Second
+)
+
+// Store whose events and checkpoints are queued and written in batches by a single goroutine.
+// Batches are written when they reach BatchSize or every FlushInterval, each in one transaction.
+// Rollbacks and the event blocks and checkpoints the listener reads flush the queue first, so they
+// see every write queued before them. Other reads may miss the writes of the last FlushInterval.
+// Failed batches are retried, and events that still cannot be written are dropped, see write.
+type Writer struct {
+	Store
+
+	BatchSize     int
+	FlushInterval time.Duration
+
+	// Called from the writer goroutine with the events of every batch once they are committed
+	OnWrite func(events []*EventRecord)
+
+	writes  chan write
+	flushes chan chan error
+
+	// Lowest block of the events dropped so far by chain, only used by the writer goroutine
+	dropped map[int64]uint64
+}
+
+var _ Store = (*Writer)(nil)
+
+// Queued event or checkpoint
+type write struct {
+	event *Event
+
+	checkpoint CheckpointKey
+	block      uint64
+}
+
+// Creates a new Writer in front of a store, it does not write anything until it is started
+func NewWriter(store Store, batchSize int, flushInterval time.Duration) *Writer {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+
+	return &Writer{
+		Store:         store,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		writes:        make(chan write, batchSize),
+		flushes:       make(chan chan error),
+		dropped:       make(map[int64]uint64),
+	}
+}
+
+// Writes the queued events and checkpoints in the background
+func (w *Writer) Start(wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		w.run()
+	}()
+}
+
+func (w *Writer) run() {
+	ticker := time.NewTicker(w.FlushInterval)
+	defer ticker.Stop()
+
+	b := NewBatch()
+
+	for {
+		select {
+		case wr := <-w.writes:
+			b.add(wr)
+
+			if len(b.Events) >= w.BatchSize {
+				w.write(b)
+				b = NewBatch()
+			}
+
+		case <-ticker.C:
+			if b.Len() > 0 {
+				w.write(b)
+				b = NewBatch()
+			}
+
+		case done := <-w.flushes:
+			var err error
+
+			// Writes queued before the flush was requested are still buffered
+			for len(w.writes) > 0 {
+				b.add(<-w.writes)
+
+				if len(b.Events) >= w.BatchSize {
+					if werr := w.write(b); werr != nil {
+						err = werr
+					}
+					b = NewBatch()
+				}
+			}
+
+			if b.Len() > 0 {
+				if werr := w.write(b); werr != nil {
+					err = werr
+				}
+				b = NewBatch()
+			}
+
+			done <- err
+		}
+	}
+}
+
+func (b *Batch) add(wr write) {
+	if wr.event != nil {
+		b.Events = append(b.Events, wr.event)
+	} else {
+		b.Checkpoints[wr.checkpoint] = wr.block
+	}
+}
+
+// Writes a batch, retrying with exponential backoff. A batch that keeps failing is written one event
+// at a time so that a single bad event does not hold back the others, the events that still fail are
+// dropped. Once an event was dropped, no checkpoint of its chain at or after its block is written
+// again, by this batch or a later one, so that the scanners resume before it and scan it again on
+// restart. Only a
+// failure to write the checkpoints is returned.
+func (w *Writer) write(b *Batch) error {
+	ctx := context.Background()
+	backoff := minWriteBackoff
+
+	w.holdCheckpoints(b)
+
+	var err error
+	for attempt := 1; attempt <= maxWriteAttempts; attempt++ {
+		if err = w.Store.WriteBatch(ctx, b); err == nil {
+			w.written(b.Events)
+			return nil
+		}
+
+		log.Printf("Failed to write batch of %d events, attempt %d/%d: %v", len(b.Events), attempt, maxWriteAttempts, err.Error())
+
+		if attempt < maxWriteAttempts {
+			time.Sleep(backoff)
+
+			backoff *= 2
+			if backoff > maxWriteBackoff {
+				backoff = maxWriteBackoff
+			}
+		}
+	}
+
+	written := make([]*Event, 0, len(b.Events))
+	for _, e := range b.Events {
+		if err := w.Store.ApplyEvent(ctx, e); err != nil {
+			l := e.Log()
+			log.Printf("Dropping event %s:%d of block %d: %v", l.TxHash.Hex(), l.Index, l.BlockNumber, err.Error())
+
+			chainID := e.Domain.ChainID.Int64()
+			if dropped, ok := w.dropped[chainID]; !ok || l.BlockNumber < dropped {
+				w.dropped[chainID] = l.BlockNumber
+			}
+			continue
+		}
+
+		written = append(written, e)
+	}
+	w.written(written)
+
+	w.holdCheckpoints(b)
+	if len(b.Checkpoints) == 0 {
+		return nil
+	}
+
+	if err := w.Store.WriteBatch(ctx, &Batch{Checkpoints: b.Checkpoints}); err != nil {
+		log.Printf("Failed to write %d checkpoints: %v", len(b.Checkpoints), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Removes the checkpoints at or after the lowest dropped block of their chain from a batch
+func (w *Writer) holdCheckpoints(b *Batch) {
+	for key, block := range b.Checkpoints {
+		dropped, ok := w.dropped[key.ChainID]
+		if ok && block >= dropped {
+			log.Printf("Not writing checkpoint %s of chain %d at block %d, the event dropped at block %d has to be scanned again", key.Name, key.ChainID, block, dropped)
+			delete(b.Checkpoints, key)
+		}
+	}
+}
+
+func (w *Writer) written(events []*Event) {
+	if w.OnWrite == nil || len(events) == 0 {
+		return
+	}
+
+	records := make([]*EventRecord, 0, len(events))
+	for _, e := range events {
+		if r, err := newEventRecord(e); err == nil {
+			records = append(records, r)
+		}
+	}
+
+	w.OnWrite(records)
+}
+
+// Queues an event, it is written with the next batch. Only unsupported events return an error,
+// write errors are handled by the writer.
+func (w *Writer) ApplyEvent(ctx context.Context, e *Event) error {
+	if !e.Supported() {
+		return ErrUnsupportedEvent
+	}
+
+	return w.queue(ctx, write{event: e})
+}
+
+// Queues a checkpoint, it is written in the same transaction as the events queued before it
+func (w *Writer) PutCheckpoint(ctx context.Context, chainID int64, name string, block uint64) error {
+	return w.queue(ctx, write{checkpoint: CheckpointKey{chainID, name}, block: block})
+}
+
+func (w *Writer) queue(ctx context.Context, wr write) error {
+	select {
+	case w.writes <- wr:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Writes the queued events and checkpoints and waits for them to be committed
+func (w *Writer) Flush(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	select {
+	case w.flushes <- done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Writes the queue, then the batch
+func (w *Writer) WriteBatch(ctx context.Context, b *Batch) error {
+	if err := w.Flush(ctx); err != nil {
+		return err
+	}
+
+	return w.Store.WriteBatch(ctx, b)
+}
+
+func (w *Writer) EventBlocks(ctx context.Context, chainID int64, from uint64) (map[uint64][]common.Hash, error) {
+	if err := w.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	return w.Store.EventBlocks(ctx, chainID, from)
+}
+
+func (w *Writer) RollbackBlock(ctx context.Context, chainID int64, blockHash common.Hash) (*Rollback, error) {
+	if err := w.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	return w.Store.RollbackBlock(ctx, chainID, blockHash)
+}
+
+func (w *Writer) Checkpoint(ctx context.Context, chainID int64, name string) (uint64, bool, error) {
+	if err := w.Flush(ctx); err != nil {
+		return 0, false, err
+	}
+
+	return w.Store.Checkpoint(ctx, chainID, name)
+}
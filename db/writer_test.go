@@ -0,0 +1,282 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"goport/order"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTestWrite = errors.New("test write failure")
+
+// Events of this transaction can never be written
+const badTx = 0xff
+
+// MemoryStore recording the batches written to it, failing the first fails batches
+type batchStore struct {
+	*MemoryStore
+
+	mu      sync.Mutex
+	fails   int
+	batches []*Batch
+}
+
+func (s *batchStore) WriteBatch(ctx context.Context, b *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fails > 0 {
+		s.fails--
+		return errTestWrite
+	}
+
+	for _, e := range b.Events {
+		if e.Log().TxHash[31] == badTx {
+			return errTestWrite
+		}
+	}
+
+	s.batches = append(s.batches, b)
+
+	return s.MemoryStore.WriteBatch(ctx, b)
+}
+
+func (s *batchStore) ApplyEvent(ctx context.Context, e *Event) error {
+	if e.Log().TxHash[31] == badTx {
+		return errTestWrite
+	}
+
+	return s.MemoryStore.ApplyEvent(ctx, e)
+}
+
+// Returns the number of events of every batch written so far
+func (s *batchStore) sizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sizes := make([]int, len(s.batches))
+	for i, b := range s.batches {
+		sizes[i] = len(b.Events)
+	}
+
+	return sizes
+}
+
+func startTestWriter(t *testing.T, s *batchStore, batchSize int, flushInterval time.Duration) *Writer {
+	minWriteBackoff, maxWriteBackoff = time.Millisecond, time.Millisecond
+
+	w := NewWriter(s, batchSize, flushInterval)
+	w.Start(&sync.WaitGroup{})
+
+	return w
+}
+
+// Returns an on-chain validation of a listing, in the block numbered like its transaction
+func testEvent(tx byte) *Event {
+	return newTestValidated(newTestListing(1, 100, 0), tx, uint64(tx), time.Now())
+}
+
+func TestWriterBatches(t *testing.T) {
+	ctx := context.Background()
+	s := &batchStore{MemoryStore: NewMemoryStore()}
+	w := startTestWriter(t, s, 3, time.Hour)
+
+	for tx := byte(1); tx <= 7; tx++ {
+		if err := w.ApplyEvent(ctx, testEvent(tx)); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+	}
+
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if sizes := s.sizes(); len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Fatalf("wrote batches of %v events, want [3 3 1]", sizes)
+	}
+
+	// A partial batch is written after the flush interval without a flush
+	s = &batchStore{MemoryStore: NewMemoryStore()}
+	w = startTestWriter(t, s, 100, 10*time.Millisecond)
+
+	if err := w.ApplyEvent(ctx, testEvent(1)); err != nil {
+		t.Fatalf("ApplyEvent: %v", err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); len(s.sizes()) == 0; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("partial batch was not written after the flush interval")
+		}
+	}
+}
+
+func TestWriterFlushOrder(t *testing.T) {
+	ctx := context.Background()
+	s := &batchStore{MemoryStore: NewMemoryStore()}
+	w := startTestWriter(t, s, 100, time.Hour)
+
+	if err := w.ApplyEvent(ctx, testEvent(1)); err != nil {
+		t.Fatalf("ApplyEvent: %v", err)
+	}
+	if err := w.PutCheckpoint(ctx, 1, "test", 1); err != nil {
+		t.Fatalf("PutCheckpoint: %v", err)
+	}
+
+	// Reading the checkpoint flushes the queue, the checkpoint is written with the events before it
+	if block, ok, err := w.Checkpoint(ctx, 1, "test"); err != nil || !ok || block != 1 {
+		t.Fatalf("Checkpoint = %d, %v, %v, want 1", block, ok, err)
+	}
+
+	s.mu.Lock()
+	batches := s.batches
+	s.mu.Unlock()
+
+	if len(batches) != 1 || len(batches[0].Events) != 1 || batches[0].Checkpoints[CheckpointKey{1, "test"}] != 1 {
+		t.Fatalf("wrote %d batches, want the event and its checkpoint in one", len(batches))
+	}
+
+	if err := w.ApplyEvent(ctx, testEvent(2)); err != nil {
+		t.Fatalf("ApplyEvent: %v", err)
+	}
+
+	blocks, err := w.EventBlocks(ctx, 1, 0)
+	if err != nil {
+		t.Fatalf("EventBlocks: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("EventBlocks returned %d blocks, want the 2 queued", len(blocks))
+	}
+}
+
+func TestWriterRetries(t *testing.T) {
+	ctx := context.Background()
+	s := &batchStore{MemoryStore: NewMemoryStore(), fails: 2}
+	w := startTestWriter(t, s, 100, time.Hour)
+
+	var written []*EventRecord
+	w.OnWrite = func(events []*EventRecord) {
+		written = append(written, events...)
+	}
+
+	for tx := byte(1); tx <= 2; tx++ {
+		if err := w.ApplyEvent(ctx, testEvent(tx)); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+	}
+
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if sizes := s.sizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Fatalf("wrote batches of %v events, want [2]", sizes)
+	}
+
+	if len(written) != 2 {
+		t.Fatalf("published %d written events, want 2", len(written))
+	}
+}
+
+func TestWriterHoldsCheckpointsAfterDrop(t *testing.T) {
+	ctx := context.Background()
+	s := &batchStore{MemoryStore: NewMemoryStore()}
+	w := startTestWriter(t, s, 100, time.Hour)
+
+	put := func(name string, block uint64) {
+		if err := w.PutCheckpoint(ctx, 1, name, block); err != nil {
+			t.Fatalf("PutCheckpoint: %v", err)
+		}
+	}
+
+	// The event of block 20 keeps failing and is dropped, the other one is written on its own
+	for _, tx := range []byte{10, badTx} {
+		e := testEvent(tx)
+		if tx == badTx {
+			e = newTestValidated(newTestListing(1, 100, 0), tx, 20, time.Now())
+		}
+
+		if err := w.ApplyEvent(ctx, e); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+	}
+	put("scan", 20)
+	put("other", 15)
+
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// A later batch of the same scanner must not move its checkpoint past the dropped event
+	if err := w.ApplyEvent(ctx, testEvent(30)); err != nil {
+		t.Fatalf("ApplyEvent: %v", err)
+	}
+	put("scan", 30)
+	put("other", 19)
+
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if _, ok, err := s.Checkpoint(ctx, 1, "scan"); err != nil || ok {
+		t.Fatalf("checkpoint after the dropped event was written: %v, %v", ok, err)
+	}
+
+	// Checkpoints before the dropped event still move
+	if block, ok, err := s.Checkpoint(ctx, 1, "other"); err != nil || !ok || block != 19 {
+		t.Fatalf("Checkpoint = %d, %v, %v, want 19", block, ok, err)
+	}
+
+	events, err := s.QueryEvents(ctx, EventQuery{ChainID: 1})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("wrote %d events, want the 2 that did not fail", len(events))
+	}
+}
+
+func TestWriterHoldsCheckpointsPerChain(t *testing.T) {
+	ctx := context.Background()
+	s := &batchStore{MemoryStore: NewMemoryStore()}
+	w := startTestWriter(t, s, 100, time.Hour)
+
+	// The dropped event of chain 1 is in a lower block than the events of chain 2
+	dropped := newTestValidated(newTestListing(1, 100, 0), badTx, 5, time.Now())
+	other := newTestValidated(newTestListing(2, 100, 0), 1, 50, time.Now())
+	other.Domain = order.NewDomain(big.NewInt(2), testDomain.Version, testDomain.VerifyingContract)
+
+	for _, e := range []*Event{dropped, other} {
+		if err := w.ApplyEvent(ctx, e); err != nil {
+			t.Fatalf("ApplyEvent: %v", err)
+		}
+	}
+
+	for _, chainID := range []int64{1, 2} {
+		if err := w.PutCheckpoint(ctx, chainID, "scan", 50); err != nil {
+			t.Fatalf("PutCheckpoint: %v", err)
+		}
+	}
+
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if _, ok, err := s.Checkpoint(ctx, 1, "scan"); err != nil || ok {
+		t.Fatalf("checkpoint of chain 1 after its dropped event was written: %v, %v", ok, err)
+	}
+
+	if block, ok, err := s.Checkpoint(ctx, 2, "scan"); err != nil || !ok || block != 50 {
+		t.Fatalf("Checkpoint of chain 2 = %d, %v, %v, want 50", block, ok, err)
+	}
+
+	events, err := s.QueryEvents(ctx, EventQuery{ChainID: 2})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("wrote %d events of chain 2, want 1", len(events))
+	}
+}
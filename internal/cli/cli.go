@@ -1 +1,73 @@
-package main
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goport/config"
+	"goport/db"
+	"log"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Runs the command given on the command line, e.g. `goport migrate up`
+func Run(args []string) error {
+	if len(args) == 0 {
+		return ErrUnknownCommand
+	}
+
+	switch args[0] {
+	case "migrate":
+		return migrate(args[1:])
+	default:
+		log.Printf("Unknown command %q, usage: goport [migrate [up|down|status]]", args[0])
+		return ErrUnknownCommand
+	}
+}
+
+// Applies, rolls back or lists the database migrations, applying them by default
+func migrate(args []string) error {
+	database, err := db.Open(config.DB_DIALECT, config.DB_NAME)
+	if err != nil {
+		return err
+	}
+	defer database.DB.Close()
+
+	ctx := context.Background()
+
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch cmd {
+	case "up":
+		return database.Migrate(ctx)
+
+	case "down":
+		return database.RollbackMigrations(ctx)
+
+	case "status":
+		ms, err := database.Migrations(ctx)
+		if err != nil {
+			log.Printf("Failed to read the migration status: %v", err.Error())
+			return err
+		}
+
+		for _, m := range ms {
+			status := "pending"
+			if m.IsApplied() {
+				status = "applied"
+			}
+
+			fmt.Printf("%s_%s\t%s\n", m.Name, m.Comment, status)
+		}
+
+		return nil
+
+	default:
+		log.Printf("Unknown migrate command %q, expected up, down or status", cmd)
+		return ErrUnknownCommand
+	}
+}
@@ -0,0 +1,142 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ms "goport/db"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Backfills past events of a Seaport deployment into the database
+type Backfiller struct {
+	sl *SeaportListener
+	db ms.Store
+
+	Deployment *Deployment
+
+	// First block scanned when there is no checkpoint
+	From uint64
+
+	// Number of blocks requested per call
+	ChunkSize uint64
+}
+
+// Creates a new Backfiller for one of the listener's Seaport deployments
+func (sl *SeaportListener) NewBackfiller(db ms.Store, d *Deployment, from uint64, chunkSize uint64) *Backfiller {
+	if chunkSize == 0 {
+		chunkSize = 1
+	}
+
+	return &Backfiller{
+		sl:         sl,
+		db:         db,
+		Deployment: d,
+		From:       from,
+		ChunkSize:  chunkSize,
+	}
+}
+
+// Name of the checkpoint the backfill progress is stored under
+func (b *Backfiller) checkpoint() string {
+	return fmt.Sprintf("backfill:%s:%s", b.sl.ChainID.String(), b.Deployment.Address.Hex())
+}
+
+// Backfills up to and including block to in the background. The live listener continues after the
+// same block, see SeaportListener.ResumeAfter.
+func (b *Backfiller) Start(wg *sync.WaitGroup, to uint64) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if err := b.Run(context.Background(), to); err != nil {
+			log.Printf("Failed to backfill Seaport events: %v", err.Error())
+		}
+	}()
+}
+
+// Scans the blocks up to and including to, continuing after the last checkpoint. The checkpoint is
+// written after every chunk, so an interrupted backfill resumes where it stopped.
+func (b *Backfiller) Run(ctx context.Context, to uint64) error {
+	start := b.From
+
+	last, ok, err := b.db.Checkpoint(ctx, b.sl.ChainID.Int64(), b.checkpoint())
+	if err != nil {
+		log.Printf("Failed to read backfill checkpoint: %v", err.Error())
+		return err
+	}
+	if ok && last+1 > start {
+		start = last + 1
+	}
+
+	for start <= to {
+		end := start + b.ChunkSize - 1
+		if end > to {
+			end = to
+		}
+
+		n, err := b.chunk(ctx, start, end)
+		if err != nil {
+			log.Printf("Failed to backfill Seaport %s on %s blocks %d-%d: %v", b.Deployment.Version, b.sl.Name, start, end, err.Error())
+			return err
+		}
+
+		if err := b.db.PutCheckpoint(ctx, b.sl.ChainID.Int64(), b.checkpoint(), end); err != nil {
+			log.Printf("Failed to write backfill checkpoint: %v", err.Error())
+			return err
+		}
+
+		log.Printf("Backfilled Seaport %s on %s blocks %d-%d: %d events", b.Deployment.Version, b.sl.Name, start, end, n)
+
+		start = end + 1
+	}
+
+	return nil
+}
+
+// Writes the events of a block range in the order they were emitted
+func (b *Backfiller) chunk(ctx context.Context, start uint64, end uint64) (int, error) {
+	events, err := b.sl.filterEvents(ctx, []common.Address{b.Deployment.Address}, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := b.sl.writeEvents(b.db, events); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// Returns the events of the given Seaport deployments in a block range, in the order they were emitted
+func (sl *SeaportListener) filterEvents(ctx context.Context, addresses []common.Address, start uint64, end uint64) ([]interface{}, error) {
+	q := query(addresses)
+	q.FromBlock = new(big.Int).SetUint64(start)
+	q.ToBlock = new(big.Int).SetUint64(end)
+
+	logs, err := sl.Client.FilterLogs(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []interface{}{}
+
+	for _, l := range logs {
+		e, err := sl.decodeLog(l)
+		if errors.Is(err, ErrUnknownEvent) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
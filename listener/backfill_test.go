@@ -0,0 +1,191 @@
+package listener
+
+import (
+	"context"
+	"goport/abi"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	ms "goport/db"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func cancelledLog(seaport common.Address, tx byte) types.Log {
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+
+	return types.Log{
+		Address: seaport,
+		Topics:  []common.Hash{orderCancelledTopic, common.BytesToHash(offerer.Bytes()), {}},
+		Data:    common.BytesToHash([]byte{tx}).Bytes(),
+		TxHash:  common.BytesToHash([]byte{tx}),
+	}
+}
+
+// Returns a listener of a Seaport 1.5 deployment on the chain
+func newTestListener(t *testing.T, chain *testChain, confirmations uint64) *SeaportListener {
+	rc := chain.dial()
+	client := ethclient.NewClient(rc)
+
+	d, err := NewDeployment(abi.SeaportV1_5, big.NewInt(1), client)
+	if err != nil {
+		t.Fatalf("NewDeployment: %v", err)
+	}
+
+	return &SeaportListener{
+		Name:           "test",
+		Client:         client,
+		rpcClient:      rc,
+		ChainID:        big.NewInt(1),
+		Deployments:    []*Deployment{d},
+		Confirmations:  confirmations,
+		PollBlockRange: 10,
+		chain:          &chainTracker{headers: make(map[uint64]common.Hash)},
+		metadata:       newMetadataCache(),
+	}
+}
+
+// Returns the blocks of the stored events once there are n of them
+func eventBlocks(t *testing.T, s ms.Store, n int) []uint64 {
+	t.Helper()
+
+	var blocks []uint64
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		events, err := s.QueryEvents(context.Background(), ms.EventQuery{ChainID: 1})
+		if err != nil {
+			t.Fatalf("QueryEvents: %v", err)
+		}
+
+		blocks = blocks[:0]
+		for _, e := range events {
+			blocks = append(blocks, e.BlockNumber)
+		}
+
+		if len(blocks) >= n {
+			break
+		}
+	}
+
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	return blocks
+}
+
+func TestBackfillHandOff(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		polling := polling
+
+		name := "subscribe"
+		if polling {
+			name = "poll"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			chain := newTestChain(t, 50)
+			store := ms.NewMemoryStore()
+			sl := newTestListener(t, chain, 2)
+			seaport := sl.Deployments[0].Address
+
+			// One event for the backfill and two in the unconfirmed blocks above where it stops
+			chain.emit(cancelledLog(seaport, 1))
+			chain.mine(49)
+			chain.emit(cancelledLog(seaport, 2))
+			chain.mine(1)
+			chain.emit(cancelledLog(seaport, 3))
+
+			to, err := sl.ConfirmedHead(context.Background())
+			if err != nil {
+				t.Fatalf("ConfirmedHead: %v", err)
+			}
+			if to != 98 {
+				t.Fatalf("ConfirmedHead = %d, want 98", to)
+			}
+
+			sl.ResumeAfter(to)
+
+			var wg sync.WaitGroup
+			sl.NewBackfiller(store, sl.Deployments[0], 1, 1000).Start(&wg, to)
+			wg.Wait()
+
+			if blocks := eventBlocks(t, store, 1); len(blocks) != 1 || blocks[0] != 50 {
+				t.Fatalf("backfilled events of blocks %v, want [50]", blocks)
+			}
+
+			if polling {
+				if err := sl.pollOnce(context.Background(), store); err != nil {
+					t.Fatalf("pollOnce: %v", err)
+				}
+			} else {
+				sl.Start(&sync.WaitGroup{}, store)
+				waitFor(t, chain.subbed, "the Seaport subscription")
+			}
+
+			chain.mine(1)
+			chain.emit(cancelledLog(seaport, 4))
+			chain.mine(2)
+
+			if polling {
+				if err := sl.pollOnce(context.Background(), store); err != nil {
+					t.Fatalf("pollOnce: %v", err)
+				}
+			}
+
+			// Every block is handled by either the backfill or the listener
+			blocks := eventBlocks(t, store, 4)
+			if len(blocks) != 4 || blocks[0] != 50 || blocks[1] != 99 || blocks[2] != 100 || blocks[3] != 101 {
+				t.Fatalf("stored events of blocks %v, want [50 99 100 101]", blocks)
+			}
+		})
+	}
+}
+
+func TestBackfillFetchesMetadataOnce(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	// Two events of the same transaction and one of another transaction in the same block
+	first, second := cancelledLog(seaport, 1), cancelledLog(seaport, 1)
+	second.Index = 1
+	chain.emit(first)
+	chain.emit(second)
+	chain.emit(cancelledLog(seaport, 2))
+	chain.mine(1)
+	chain.emit(cancelledLog(seaport, 3))
+
+	var wg sync.WaitGroup
+	sl.NewBackfiller(store, sl.Deployments[0], 1, 1000).Start(&wg, 11)
+	wg.Wait()
+
+	events, err := store.QueryEvents(context.Background(), ms.EventQuery{ChainID: 1})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("stored %d events, want 4", len(events))
+	}
+
+	for _, e := range events {
+		want := time.Unix(int64(chain.header(e.BlockNumber).Time), 0).UTC()
+		if !e.BlockTimestamp.Equal(want) {
+			t.Errorf("event of block %d has timestamp %v, want %v", e.BlockNumber, e.BlockTimestamp, want)
+		}
+		if sender := common.BytesToAddress(e.TxHash[31:]); e.TxSender != sender {
+			t.Errorf("event of %s has sender %s, want %s", e.TxHash, e.TxSender, sender)
+		}
+	}
+
+	if n := chain.called("eth_getBlockByHash"); n != 2 {
+		t.Errorf("fetched blocks %d times, want 2", n)
+	}
+	if n := chain.called("eth_getTransactionByHash"); n != 3 {
+		t.Errorf("fetched transactions %d times, want 3", n)
+	}
+}
@@ -0,0 +1,401 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Chain served over a WebSocket JSON-RPC endpoint, with just enough of the eth namespace for the
+// listeners. Blocks are added with mine and logs with emit, both are pushed to the subscriptions.
+type testChain struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	head     uint64
+	headers  []*types.Header
+	logs     []types.Log
+	subs     map[rpc.ID]*testLogSub
+	headSubs map[rpc.ID]*rpc.Notifier
+	ranges   [][2]uint64
+	conns    []net.Conn
+	server   *httptest.Server
+	subbed   chan struct{}
+	dropped  bool
+
+	// Number of calls of the eth methods by name
+	calls map[string]int
+
+	// Called by eth_getBlockByHash if set
+	onBlockByHash func()
+}
+
+type testLogSub struct {
+	notifier *rpc.Notifier
+	filter   testFilter
+}
+
+// Filter argument of eth_getLogs and eth_subscribe as sent by ethclient
+type testFilter struct {
+	FromBlock string           `json:"fromBlock"`
+	ToBlock   string           `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+func (f testFilter) match(l types.Log) bool {
+	if len(f.Addresses) > 0 && !containsAddress(f.Addresses, l.Address) {
+		return false
+	}
+
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if i >= len(l.Topics) || !containsHash(topics, l.Topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAddress(list []common.Address, a common.Address) bool {
+	for _, x := range list {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(list []common.Hash, h common.Hash) bool {
+	for _, x := range list {
+		if x == h {
+			return true
+		}
+	}
+	return false
+}
+
+// Starts a chain at block head
+func newTestChain(t *testing.T, head uint64) *testChain {
+	c := &testChain{
+		t:        t,
+		subs:     make(map[rpc.ID]*testLogSub),
+		headSubs: make(map[rpc.ID]*rpc.Notifier),
+		subbed:   make(chan struct{}, 16),
+		calls:    make(map[string]int),
+	}
+	c.headers = []*types.Header{{Number: new(big.Int), Difficulty: new(big.Int)}}
+	c.mine(head)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &testEthAPI{c}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	c.server = httptest.NewUnstartedServer(server.WebsocketHandler([]string{"*"}))
+	c.server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			c.mu.Lock()
+			c.conns = append(c.conns, conn)
+			c.mu.Unlock()
+		}
+	}
+	c.server.Start()
+	t.Cleanup(c.server.Close)
+
+	return c
+}
+
+// Returns a connection to the chain, it reconnects on its next request after drop
+func (c *testChain) dial() *rpc.Client {
+	client, err := rpc.Dial("ws" + strings.TrimPrefix(c.server.URL, "http"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.t.Cleanup(client.Close)
+
+	return client
+}
+
+// Returns a client of the chain
+func (c *testChain) client() *ethclient.Client {
+	return ethclient.NewClient(c.dial())
+}
+
+// Closes the connections of the clients, failing their subscriptions
+func (c *testChain) drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, conn := range c.conns {
+		conn.Close()
+	}
+	c.conns = nil
+	c.dropped = true
+}
+
+// Adds blocks to the chain, they are pushed to the head subscriptions unless the clients were dropped
+func (c *testChain) mine(n uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := uint64(0); i < n; i++ {
+		parent := c.headers[c.head]
+		c.head++
+
+		h := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).SetUint64(c.head),
+			Difficulty: new(big.Int),
+			Time:       1_700_000_000 + 12*c.head,
+		}
+		c.headers = append(c.headers, h)
+
+		if c.dropped {
+			continue
+		}
+
+		for id, notifier := range c.headSubs {
+			notifier.Notify(id, h)
+		}
+	}
+
+	return c.head
+}
+
+// Replaces the last depth blocks with other blocks of the same numbers and drops their logs, without
+// notifying the subscriptions like a reorg while the clients are disconnected
+func (c *testChain) reorg(depth uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fork := c.head - depth
+	for n := fork + 1; n <= c.head; n++ {
+		c.headers[n] = &types.Header{
+			ParentHash: c.headers[n-1].Hash(),
+			Number:     new(big.Int).SetUint64(n),
+			Difficulty: new(big.Int),
+			Time:       1_700_000_000 + 12*n,
+			Extra:      []byte("reorg"),
+		}
+	}
+
+	logs := c.logs[:0]
+	for _, l := range c.logs {
+		if l.BlockNumber <= fork {
+			logs = append(logs, l)
+		}
+	}
+	c.logs = logs
+}
+
+// Adds a log at the head block, it is pushed to the subscriptions unless the clients were dropped
+func (c *testChain) emit(l types.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l.BlockNumber = c.head
+	l.BlockHash = c.headers[c.head].Hash()
+	c.logs = append(c.logs, l)
+
+	if c.dropped {
+		return
+	}
+
+	for id, sub := range c.subs {
+		if sub.filter.match(l) {
+			sub.notifier.Notify(id, l)
+		}
+	}
+}
+
+// Returns the header of a block
+func (c *testChain) header(n uint64) *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.headers[n]
+}
+
+// Returns the last log emitted
+func (c *testChain) lastLog() types.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.logs[len(c.logs)-1]
+}
+
+// Returns the number of calls of an eth method so far
+func (c *testChain) called(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[method]
+}
+
+// Returns the block ranges of the eth_getLogs calls so far
+func (c *testChain) filtered() [][2]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][2]uint64{}, c.ranges...)
+}
+
+type testEthAPI struct {
+	c *testChain
+}
+
+func (api *testEthAPI) BlockNumber() hexutil.Uint64 {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+
+	return hexutil.Uint64(api.c.head)
+}
+
+func (api *testEthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (api *testEthAPI) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+
+	n, err := api.c.blockArg(number)
+	if err != nil || n > api.c.head {
+		return nil, err
+	}
+
+	return api.c.headers[n], nil
+}
+
+func (api *testEthAPI) GetBlockByHash(hash common.Hash, full bool) (*types.Header, error) {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+
+	api.c.calls["eth_getBlockByHash"]++
+	if api.c.onBlockByHash != nil {
+		api.c.onBlockByHash()
+	}
+
+	for _, h := range api.c.headers {
+		if h.Hash() == hash {
+			return h, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns the sender of a transaction, the address ending with the last byte of its hash
+func (api *testEthAPI) GetTransactionByHash(hash common.Hash) map[string]interface{} {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+
+	api.c.calls["eth_getTransactionByHash"]++
+
+	return map[string]interface{}{"hash": hash, "from": common.BytesToAddress(hash[31:])}
+}
+
+func (api *testEthAPI) GetLogs(f testFilter) ([]types.Log, error) {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+
+	from, err := api.c.blockArg(f.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.c.blockArg(f.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	api.c.ranges = append(api.c.ranges, [2]uint64{from, to})
+
+	logs := []types.Log{}
+	for _, l := range api.c.logs {
+		if l.BlockNumber >= from && l.BlockNumber <= to && f.match(l) {
+			logs = append(logs, l)
+		}
+	}
+
+	return logs, nil
+}
+
+func (api *testEthAPI) Logs(ctx context.Context, f testFilter) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+
+	api.c.mu.Lock()
+	api.c.subs[sub.ID] = &testLogSub{notifier: notifier, filter: f}
+	api.c.dropped = false
+	api.c.mu.Unlock()
+
+	go func() {
+		<-sub.Err()
+
+		api.c.mu.Lock()
+		delete(api.c.subs, sub.ID)
+		api.c.mu.Unlock()
+	}()
+
+	api.c.subbed <- struct{}{}
+
+	return sub, nil
+}
+
+func (api *testEthAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+
+	api.c.mu.Lock()
+	api.c.headSubs[sub.ID] = notifier
+	api.c.mu.Unlock()
+
+	go func() {
+		<-sub.Err()
+
+		api.c.mu.Lock()
+		delete(api.c.headSubs, sub.ID)
+		api.c.mu.Unlock()
+	}()
+
+	return sub, nil
+}
+
+// Must be called with mu held
+func (c *testChain) blockArg(s string) (uint64, error) {
+	switch s {
+	case "", "latest", "pending":
+		return c.head, nil
+	}
+
+	n, err := hexutil.DecodeUint64(s)
+	if err != nil {
+		return 0, errors.New("invalid block number")
+	}
+
+	return n, nil
+}
@@ -0,0 +1,145 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"goport/abi"
+	"goport/order"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrInvalidDeployment = errors.New("invalid Seaport deployment, expected a version or version:address")
+
+// Seaport deployment followed by the listener
+type Deployment struct {
+	ChainID *big.Int
+	Version string
+	Address common.Address
+
+	// Read-only calls into the deployment
+	Seaport abi.SeaportReader
+
+	// CounterIncremented, OrderCancelled and OrderFulfilled did not change since 1.1, so they are
+	// parsed with the 1.1 binding on every version
+	events *abi.SeaportFilterer
+
+	// OrderValidated carries the order parameters since 1.2 and is parsed with the binding of the version
+	parseValidated func(types.Log) (*abi.SeaportOrderValidated, error)
+}
+
+// Creates a new Deployment from a "version" or "version:address" entry, the canonical address of
+// the version is used when none is given
+func NewDeployment(entry string, chainID *big.Int, backend bind.ContractBackend) (*Deployment, error) {
+	version, address, hasAddress := strings.Cut(strings.TrimSpace(entry), ":")
+
+	addr, ok := abi.SeaportAddresses[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", abi.ErrUnknownSeaportVersion, version)
+	}
+
+	if hasAddress {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDeployment, entry)
+		}
+		addr = common.HexToAddress(address)
+	}
+
+	d := &Deployment{ChainID: chainID, Version: version, Address: addr}
+
+	var err error
+
+	if d.Seaport, err = abi.NewSeaportReader(version, addr, backend); err != nil {
+		return nil, err
+	}
+
+	if d.events, err = abi.NewSeaportFilterer(addr, backend); err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case abi.SeaportV1_1:
+		d.parseValidated = d.events.ParseOrderValidated
+
+	case abi.SeaportV1_4:
+		f, err := abi.NewSeaportV14Filterer(addr, backend)
+		if err != nil {
+			return nil, err
+		}
+		d.parseValidated = func(l types.Log) (*abi.SeaportOrderValidated, error) {
+			e, err := f.ParseOrderValidated(l)
+			if err != nil {
+				return nil, err
+			}
+			return newOrderValidated(e.OrderHash, e.OrderParameters, e.Raw), nil
+		}
+
+	case abi.SeaportV1_5:
+		f, err := abi.NewSeaportV15Filterer(addr, backend)
+		if err != nil {
+			return nil, err
+		}
+		d.parseValidated = func(l types.Log) (*abi.SeaportOrderValidated, error) {
+			e, err := f.ParseOrderValidated(l)
+			if err != nil {
+				return nil, err
+			}
+			return newOrderValidated(e.OrderHash, e.OrderParameters, e.Raw), nil
+		}
+
+	case abi.SeaportV1_6:
+		f, err := abi.NewSeaportV16Filterer(addr, backend)
+		if err != nil {
+			return nil, err
+		}
+		d.parseValidated = func(l types.Log) (*abi.SeaportOrderValidated, error) {
+			e, err := f.ParseOrderValidated(l)
+			if err != nil {
+				return nil, err
+			}
+			return newOrderValidated(e.OrderHash, e.OrderParameters, e.Raw), nil
+		}
+	}
+
+	return d, nil
+}
+
+// Returns the EIP-712 domain orders of the deployment are signed for
+func (d *Deployment) Domain() order.Domain {
+	return order.NewDomain(d.ChainID, d.Version, d.Address)
+}
+
+// Converts an OrderValidated event of Seaport 1.2 and later into the 1.1 event stored in the database
+func newOrderValidated(orderHash [32]byte, params abi.OrderParameters, raw types.Log) *abi.SeaportOrderValidated {
+	return &abi.SeaportOrderValidated{
+		OrderHash: orderHash,
+		Offerer:   params.Offerer,
+		Zone:      params.Zone,
+		Raw:       raw,
+	}
+}
+
+// Returns the deployment at the given address, or nil if it is not followed
+func (sl *SeaportListener) deployment(address common.Address) *Deployment {
+	for _, d := range sl.Deployments {
+		if d.Address == address {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// Returns the addresses of the followed deployments
+func (sl *SeaportListener) addresses() []common.Address {
+	addrs := make([]common.Address, len(sl.Deployments))
+	for i, d := range sl.Deployments {
+		addrs[i] = d.Address
+	}
+
+	return addrs
+}
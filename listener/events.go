@@ -0,0 +1,136 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	ms "goport/db"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrUnknownEvent = errors.New("unknown Seaport event")
+
+var (
+	counterIncrementedTopic = mustEventID(abi.SeaportMetaData, "CounterIncremented")
+	orderCancelledTopic     = mustEventID(abi.SeaportMetaData, "OrderCancelled")
+	orderValidatedTopic     = mustEventID(abi.SeaportMetaData, "OrderValidated")
+	orderFulfilledTopic     = mustEventID(abi.SeaportMetaData, "OrderFulfilled")
+
+	// OrderValidated of Seaport 1.2 and later
+	orderValidatedV2Topic = mustEventID(abi.SeaportV14MetaData, "OrderValidated")
+)
+
+// Returns the query matching every log of the given Seaport deployments
+func query(addresses []common.Address) ethereum.FilterQuery {
+	return ethereum.FilterQuery{Addresses: addresses}
+}
+
+// Decodes a Seaport log by its first topic. Logs of events the listener does not handle return ErrUnknownEvent.
+func (sl *SeaportListener) decodeLog(l types.Log) (interface{}, error) {
+	d := sl.deployment(l.Address)
+	if d == nil || len(l.Topics) == 0 {
+		return nil, ErrUnknownEvent
+	}
+
+	switch l.Topics[0] {
+	case counterIncrementedTopic:
+		return d.events.ParseCounterIncremented(l)
+	case orderCancelledTopic:
+		return d.events.ParseOrderCancelled(l)
+	case orderValidatedTopic, orderValidatedV2Topic:
+		return d.parseValidated(l)
+	case orderFulfilledTopic:
+		return d.events.ParseOrderFulfilled(l)
+	}
+
+	return nil, ErrUnknownEvent
+}
+
+// Returns the name of an event for logging
+func eventName(event interface{}) string {
+	switch event.(type) {
+	case *abi.SeaportCounterIncremented:
+		return "CounterIncremented"
+	case *abi.SeaportOrderCancelled:
+		return "OrderCancelled"
+	case *abi.SeaportOrderValidated:
+		return "OrderValidated"
+	case *abi.SeaportOrderFulfilled:
+		return "OrderFulfilled"
+	}
+
+	return "unknown event"
+}
+
+// Writes a Seaport event to the database. Live and historical events go through here,
+// so both end up in the same state.
+func (sl *SeaportListener) writeEvent(db ms.Store, event interface{}) error {
+	l := eventLog(event)
+
+	d := sl.deployment(l.Address)
+	if d == nil {
+		return ErrUnknownEvent
+	}
+
+	e := &ms.Event{
+		Domain: d.Domain(),
+		Data:   event,
+	}
+
+	if !e.Supported() {
+		return ErrUnknownEvent
+	}
+
+	e.BlockTimestamp, e.TxSender = sl.eventMetadata(l)
+
+	return db.ApplyEvent(context.Background(), e)
+}
+
+// Writes events in order, fetching the metadata of every metadataBatchSize events at once
+func (sl *SeaportListener) writeEvents(db ms.Store, events []interface{}) error {
+	for start := 0; start < len(events); start += metadataBatchSize {
+		end := start + metadataBatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+
+		sl.fetchMetadata(context.Background(), eventLogs(events[start:end]))
+
+		for _, e := range events[start:end] {
+			if err := sl.writeEvent(db, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the logs events were decoded from
+func eventLogs(events []interface{}) []types.Log {
+	logs := make([]types.Log, len(events))
+	for i, e := range events {
+		logs[i] = eventLog(e)
+	}
+
+	return logs
+}
+
+// Returns the log an event was decoded from
+func eventLog(event interface{}) types.Log {
+	switch e := event.(type) {
+	case *abi.SeaportCounterIncremented:
+		return e.Raw
+	case *abi.SeaportOrderCancelled:
+		return e.Raw
+	case *abi.SeaportOrderValidated:
+		return e.Raw
+	case *abi.SeaportOrderFulfilled:
+		return e.Raw
+	}
+
+	return types.Log{}
+}
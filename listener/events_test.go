@@ -0,0 +1,124 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"math/big"
+	"sync"
+	"testing"
+
+	ms "goport/db"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func counterLog(seaport common.Address, counter int64) types.Log {
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+
+	return types.Log{
+		Address: seaport,
+		Topics:  []common.Hash{counterIncrementedTopic, common.BytesToHash(offerer.Bytes())},
+		Data:    common.BigToHash(big.NewInt(counter)).Bytes(),
+		TxHash:  common.BigToHash(big.NewInt(counter + 100)),
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	chain := newTestChain(t, 10)
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	other := cancelledLog(seaport, 1)
+	other.Address = common.HexToAddress("0x0000000000000000000000000000000000000bad")
+
+	unknown := cancelledLog(seaport, 1)
+	unknown.Topics[0] = transferTopic
+
+	cases := []struct {
+		name string
+		log  types.Log
+		want string
+		err  error
+	}{
+		{"counter incremented", counterLog(seaport, 3), "CounterIncremented", nil},
+		{"order cancelled", cancelledLog(seaport, 1), "OrderCancelled", nil},
+		{"other contract", other, "", ErrUnknownEvent},
+		{"unknown topic", unknown, "", ErrUnknownEvent},
+		{"anonymous", types.Log{Address: seaport}, "", ErrUnknownEvent},
+	}
+
+	for _, tc := range cases {
+		event, err := sl.decodeLog(tc.log)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: decodeLog error %v, want %v", tc.name, err, tc.err)
+			continue
+		}
+		if err == nil && eventName(event) != tc.want {
+			t.Errorf("%s: decoded %s, want %s", tc.name, eventName(event), tc.want)
+		}
+	}
+
+	event, err := sl.decodeLog(counterLog(seaport, 3))
+	if err != nil {
+		t.Fatalf("decodeLog: %v", err)
+	}
+	if c := event.(*abi.SeaportCounterIncremented); c.NewCounter.Int64() != 3 {
+		t.Fatalf("decoded counter %v, want 3", c.NewCounter)
+	}
+}
+
+func TestFilterEventsKeepsLogOrder(t *testing.T) {
+	chain := newTestChain(t, 10)
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	// A cancel, then an unknown event, then a counter increment in the same block
+	unknown := cancelledLog(seaport, 2)
+	unknown.Topics[0] = transferTopic
+
+	for i, l := range []types.Log{cancelledLog(seaport, 1), unknown, counterLog(seaport, 1)} {
+		l.Index = uint(i)
+		chain.emit(l)
+	}
+
+	events, err := sl.filterEvents(context.Background(), sl.addresses(), 10, 10)
+	if err != nil {
+		t.Fatalf("filterEvents: %v", err)
+	}
+
+	if len(events) != 2 || eventName(events[0]) != "OrderCancelled" || eventName(events[1]) != "CounterIncremented" {
+		names := []string{}
+		for _, e := range events {
+			names = append(names, eventName(e))
+		}
+		t.Fatalf("filtered %v, want [OrderCancelled CounterIncremented]", names)
+	}
+}
+
+func TestListenerSubscribesOnce(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	sl.Start(&sync.WaitGroup{}, store)
+	waitFor(t, chain.subbed, "the Seaport subscription")
+
+	chain.mu.Lock()
+	subs := len(chain.subs)
+	chain.mu.Unlock()
+
+	if subs != 1 {
+		t.Fatalf("%d log subscriptions, want 1 for every event type", subs)
+	}
+
+	// Events of different types arrive through the same subscription
+	chain.emit(cancelledLog(seaport, 1))
+	chain.emit(counterLog(seaport, 1))
+
+	if blocks := eventBlocks(t, store, 2); len(blocks) != 2 {
+		t.Fatalf("stored events of blocks %v, want 2 events", blocks)
+	}
+}
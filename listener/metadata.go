@@ -0,0 +1,147 @@
+package listener
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Number of block timestamps and transaction senders remembered, events of the same block or
+// transaction are usually written together
+const metadataCacheSize = 1024
+
+// Number of logs whose metadata is fetched in a single batch request
+const metadataBatchSize = 100
+
+// Remembers the block timestamps and transaction senders fetched for recent events
+type metadataCache struct {
+	mu sync.Mutex
+
+	timestamps map[common.Hash]time.Time
+	senders    map[common.Hash]common.Address
+
+	// Keys in the order they were added, the oldest are evicted first
+	blocks []common.Hash
+	txs    []common.Hash
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{
+		timestamps: make(map[common.Hash]time.Time),
+		senders:    make(map[common.Hash]common.Address),
+	}
+}
+
+// Fields of eth_getBlockByHash and eth_getTransactionByHash results the listener stores
+type blockMetadata struct {
+	Time hexutil.Uint64 `json:"timestamp"`
+}
+
+type txMetadata struct {
+	From common.Address `json:"from"`
+}
+
+// Returns the timestamp of the block a log was emitted in and the sender of its transaction, zero
+// if they could not be fetched
+func (sl *SeaportListener) eventMetadata(l types.Log) (time.Time, common.Address) {
+	sl.fetchMetadata(context.Background(), []types.Log{l})
+
+	c := sl.metadata
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timestamps[l.BlockHash], c.senders[l.TxHash]
+}
+
+// Fetches the block timestamps and transaction senders of logs that are not cached yet, with one
+// batch request for every metadataBatchSize logs. The sender is the from field of the transaction,
+// so it does not need another request.
+func (sl *SeaportListener) fetchMetadata(ctx context.Context, logs []types.Log) {
+	for start := 0; start < len(logs); start += metadataBatchSize {
+		end := start + metadataBatchSize
+		if end > len(logs) {
+			end = len(logs)
+		}
+
+		sl.fetchMetadataBatch(ctx, logs[start:end])
+	}
+}
+
+func (sl *SeaportListener) fetchMetadataBatch(ctx context.Context, logs []types.Log) {
+	c := sl.metadata
+
+	var (
+		batch  []rpc.BatchElem
+		blocks = make(map[common.Hash]**blockMetadata)
+		txs    = make(map[common.Hash]**txMetadata)
+	)
+
+	c.mu.Lock()
+	for _, l := range logs {
+		if _, ok := c.timestamps[l.BlockHash]; !ok && blocks[l.BlockHash] == nil {
+			res := new(*blockMetadata)
+			blocks[l.BlockHash] = res
+			batch = append(batch, rpc.BatchElem{Method: "eth_getBlockByHash", Args: []interface{}{l.BlockHash, false}, Result: res})
+		}
+
+		if _, ok := c.senders[l.TxHash]; !ok && txs[l.TxHash] == nil {
+			res := new(*txMetadata)
+			txs[l.TxHash] = res
+			batch = append(batch, rpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{l.TxHash}, Result: res})
+		}
+	}
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := sl.rpcClient.BatchCallContext(ctx, batch); err != nil {
+		log.Printf("Failed to get block and transaction metadata on %s: %v", sl.Name, err.Error())
+		return
+	}
+
+	for _, elem := range batch {
+		if elem.Error != nil {
+			log.Printf("Failed to get %s %v on %s: %v", elem.Method, elem.Args[0], sl.Name, elem.Error.Error())
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for hash, res := range blocks {
+		if _, ok := c.timestamps[hash]; ok || *res == nil {
+			continue
+		}
+
+		c.timestamps[hash] = time.Unix(int64((*res).Time), 0).UTC()
+		c.blocks = append(c.blocks, hash)
+
+		if len(c.blocks) > metadataCacheSize {
+			delete(c.timestamps, c.blocks[0])
+			c.blocks = c.blocks[1:]
+		}
+	}
+
+	for hash, res := range txs {
+		if _, ok := c.senders[hash]; ok || *res == nil {
+			continue
+		}
+
+		c.senders[hash] = (*res).From
+		c.txs = append(c.txs, hash)
+
+		if len(c.txs) > metadataCacheSize {
+			delete(c.senders, c.txs[0])
+			c.txs = c.txs[1:]
+		}
+	}
+}
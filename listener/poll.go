@@ -0,0 +1,87 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	ms "goport/db"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRPCMode = errors.New("invalid RPC mode, expected subscribe, poll or auto")
+
+// Returns true if the endpoint has to be polled with eth_getLogs. In auto mode HTTP endpoints are
+// polled, since they do not support eth_subscribe.
+func usePolling(url string, mode string) (bool, error) {
+	switch mode {
+	case "subscribe":
+		return false, nil
+	case "poll":
+		return true, nil
+	case "auto":
+		u := strings.ToLower(url)
+		return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://"), nil
+	}
+
+	return false, ErrInvalidRPCMode
+}
+
+// Polls the chain for new heads and Seaport events, failed polls are retried on the next tick
+func (sl *SeaportListener) poll(db ms.Store) {
+	ticker := time.NewTicker(sl.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := sl.pollOnce(context.Background(), db); err != nil {
+			log.Printf("Failed to poll Seaport events on %s: %v", sl.Name, err.Error())
+		}
+
+		<-ticker.C
+	}
+}
+
+func (sl *SeaportListener) pollOnce(ctx context.Context, db ms.Store) error {
+	head, err := sl.Client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Logs are fetched after the block the backfill stopped at, or from the head without a backfill
+	if sl.lastHead == 0 && head > 0 {
+		sl.lastHead = head - 1
+	}
+	if sl.lastBlock == 0 && head > 0 {
+		sl.lastBlock = head - 1
+	}
+
+	// Heads are followed block by block, a reorg is only noticed if the parent of a block is known
+	from := sl.lastHead + 1
+	if head > reorgWindow && from < head-reorgWindow {
+		from = head - reorgWindow
+	}
+
+	for n := from; n <= head; n++ {
+		h, err := sl.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return err
+		}
+
+		// Logs of the blocks that were replaced are fetched again from the new chain
+		if fork, reorged := sl.newHead(db, h); reorged && fork < sl.lastBlock {
+			sl.lastBlock = fork
+		}
+
+		sl.lastHead = n
+	}
+
+	if _, err := sl.handleRange(ctx, db, sl.lastBlock+1, head, sl.PollBlockRange); err != nil {
+		return err
+	}
+
+	// Events of the polled range may already be confirmed
+	sl.writeConfirmed(db)
+
+	return nil
+}
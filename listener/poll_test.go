@@ -0,0 +1,125 @@
+package listener
+
+import (
+	"context"
+	"testing"
+
+	ms "goport/db"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestUsePolling(t *testing.T) {
+	cases := []struct {
+		url  string
+		mode string
+		want bool
+		err  error
+	}{
+		{"https://rpc.example", "auto", true, nil},
+		{"HTTP://rpc.example", "auto", true, nil},
+		{"wss://rpc.example", "auto", false, nil},
+		{"/var/run/geth.ipc", "auto", false, nil},
+		{"https://rpc.example", "subscribe", false, nil},
+		{"wss://rpc.example", "poll", true, nil},
+		{"wss://rpc.example", "stream", false, ErrInvalidRPCMode},
+	}
+
+	for _, tc := range cases {
+		polling, err := usePolling(tc.url, tc.mode)
+		if polling != tc.want || err != tc.err {
+			t.Errorf("usePolling(%q, %q) = %v, %v, want %v, %v", tc.url, tc.mode, polling, err, tc.want, tc.err)
+		}
+	}
+}
+
+// Returns the orders of the stored events
+func storedOrders(t *testing.T, s ms.Store) []common.Hash {
+	t.Helper()
+
+	events, err := s.QueryEvents(context.Background(), ms.EventQuery{ChainID: 1})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+
+	hashes := []common.Hash{}
+	for _, e := range events {
+		hashes = append(hashes, e.OrderHash)
+	}
+
+	return hashes
+}
+
+func TestPollOnceInChunks(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	// The first poll starts at the head
+	chain.emit(cancelledLog(seaport, 1))
+	if err := sl.pollOnce(context.Background(), store); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+	if n := storedEvents(t, store); n != 1 {
+		t.Fatalf("stored %d events, want 1", n)
+	}
+
+	chain.mine(10)
+	chain.emit(cancelledLog(seaport, 2))
+	chain.mine(15)
+	chain.emit(cancelledLog(seaport, 3))
+
+	if err := sl.pollOnce(context.Background(), store); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+	if n := storedEvents(t, store); n != 3 {
+		t.Fatalf("stored %d events, want 3", n)
+	}
+
+	// The blocks after the last poll are fetched in ranges of PollBlockRange blocks
+	want := [][2]uint64{{10, 10}, {11, 20}, {21, 30}, {31, 35}}
+	ranges := chain.filtered()
+	if len(ranges) != len(want) {
+		t.Fatalf("polled eth_getLogs over %v, want %v", ranges, want)
+	}
+	for i := range want {
+		if ranges[i] != want[i] {
+			t.Fatalf("polled eth_getLogs over %v, want %v", ranges, want)
+		}
+	}
+
+	// Nothing new on the chain
+	if err := sl.pollOnce(context.Background(), store); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+	if n := len(chain.filtered()); n != len(want) {
+		t.Fatalf("polled %d ranges without new blocks, want %d", n, len(want))
+	}
+}
+
+func TestPollOnceRefetchesReorgedBlocks(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	chain.emit(cancelledLog(seaport, 1))
+	if err := sl.pollOnce(context.Background(), store); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+
+	// Block 10 is replaced by a block with another event, which is noticed by the next block
+	chain.reorg(1)
+	chain.emit(cancelledLog(seaport, 2))
+	chain.mine(1)
+
+	if err := sl.pollOnce(context.Background(), store); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+
+	orders := storedOrders(t, store)
+	if len(orders) != 1 || orders[0] != common.BytesToHash([]byte{2}) {
+		t.Fatalf("stored events of orders %v, want only the event of the new block 10", orders)
+	}
+}
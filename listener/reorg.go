@@ -0,0 +1,358 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	ms "goport/db"
+	"log"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Number of blocks below the confirmation depth whose hashes are remembered to detect reorgs
+const reorgWindow = 128
+
+// Tracks the canonical chain and holds events back until they are confirmed
+type chainTracker struct {
+	mu sync.Mutex
+
+	// Canonical block hashes of the recent blocks by number
+	headers map[uint64]common.Hash
+	head    uint64
+
+	// Events waiting for enough confirmations
+	pending []interface{}
+}
+
+// Queues or writes a live event. Removed logs roll back what was written for their block.
+func (sl *SeaportListener) handleEvent(db ms.Store, event interface{}) error {
+	l := eventLog(event)
+
+	if l.Removed {
+		sl.dropPending(l.BlockHash)
+		return sl.rollback(db, l.BlockHash)
+	}
+
+	if sl.Confirmations == 0 {
+		return sl.writeEvent(db, event)
+	}
+
+	sl.chain.mu.Lock()
+	sl.chain.pending = append(sl.chain.pending, event)
+	sl.chain.mu.Unlock()
+
+	return nil
+}
+
+// Writes the events confirmed by a new head and rolls back the events of reorged out blocks.
+// Returns the last block shared with the previous chain if it reorged.
+func (sl *SeaportListener) newHead(db ms.Store, h *types.Header) (uint64, bool) {
+	fork, reorged := sl.trackHead(h)
+
+	if reorged {
+		log.Printf("Chain reorganized below block %d, rolling back events after block %d", h.Number.Uint64(), fork)
+
+		if err := sl.rollbackAfter(db, fork); err != nil {
+			log.Printf("Failed to roll back reorged events: %v", err.Error())
+		}
+	}
+
+	sl.writeConfirmed(db)
+
+	return fork, reorged
+}
+
+// Records a new head and returns the last block shared with the previous chain if it reorged. The
+// blocks of a new branch are fetched without holding the tracker lock.
+func (sl *SeaportListener) trackHead(h *types.Header) (uint64, bool) {
+	number := h.Number.Uint64()
+	fork, reorged := number, false
+
+	// Walk back along the new chain until it joins the blocks seen before
+	branch := make(map[uint64]common.Hash)
+	unknown := false
+
+	hash, parent := h.Hash(), h.ParentHash
+	for n := number; n > 0; n-- {
+		branch[n] = hash
+
+		sl.chain.mu.Lock()
+		known, ok := sl.chain.headers[n-1]
+		sl.chain.mu.Unlock()
+
+		if !ok || known == parent {
+			fork = n - 1
+			break
+		}
+
+		reorged = true
+
+		header, err := sl.Client.HeaderByHash(context.Background(), parent)
+		if err != nil {
+			log.Printf("Failed to get block %s: %v", parent.Hex(), err.Error())
+			// Blocks whose hash is unknown are checked against the node on the next rollback
+			unknown = true
+			fork = n - 1
+			break
+		}
+
+		hash, parent = header.Hash(), header.ParentHash
+	}
+
+	sl.chain.mu.Lock()
+	defer sl.chain.mu.Unlock()
+
+	for n, hash := range branch {
+		if old, ok := sl.chain.headers[n]; ok && old != hash {
+			reorged = true
+		}
+		sl.chain.headers[n] = hash
+	}
+
+	if unknown {
+		delete(sl.chain.headers, fork)
+	}
+
+	// A reorg to a shorter chain leaves blocks above the new head
+	for n := range sl.chain.headers {
+		if n > number {
+			delete(sl.chain.headers, n)
+			reorged = true
+		}
+		if n+sl.Confirmations+reorgWindow < number {
+			delete(sl.chain.headers, n)
+		}
+	}
+
+	sl.chain.head = number
+
+	return fork, reorged
+}
+
+// Checks the hashes of the unconfirmed blocks seen before a reconnection against the node and rolls
+// back the events of the blocks that were reorged out while disconnected. Returns an error if the
+// node could not be asked, the hashes are then kept.
+func (sl *SeaportListener) verifyHeads(ctx context.Context, db ms.Store) error {
+	sl.chain.mu.Lock()
+	head := sl.chain.head
+	low := uint64(0)
+	if head > sl.Confirmations {
+		low = head - sl.Confirmations
+	}
+
+	stored := make(map[uint64]common.Hash)
+	for n, hash := range sl.chain.headers {
+		if n >= low {
+			stored[n] = hash
+		}
+	}
+	sl.chain.mu.Unlock()
+
+	// Walk down from the head until a block is still canonical, the blocks below it are too
+	fork, reorged := head, false
+	for i := uint64(0); i <= head-low; i++ {
+		n := head - i
+
+		hash, ok := stored[n]
+		if !ok {
+			continue
+		}
+
+		h, err := sl.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && h.Hash() == hash {
+			break
+		}
+
+		if n == 0 {
+			break
+		}
+		fork, reorged = n-1, true
+	}
+
+	if !reorged {
+		return nil
+	}
+
+	sl.chain.mu.Lock()
+	for n := range sl.chain.headers {
+		if n > fork {
+			delete(sl.chain.headers, n)
+		}
+	}
+	sl.chain.mu.Unlock()
+
+	log.Printf("Chain %s reorganized after block %d while disconnected, rolling back its events", sl.Name, fork)
+
+	return sl.rollbackAfter(db, fork)
+}
+
+// Returns the canonical hash of a block
+func (sl *SeaportListener) canonicalHash(number uint64) (common.Hash, error) {
+	sl.chain.mu.Lock()
+	hash, ok := sl.chain.headers[number]
+	sl.chain.mu.Unlock()
+
+	if ok {
+		return hash, nil
+	}
+
+	h, err := sl.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return h.Hash(), nil
+}
+
+// Writes the pending events that reached the confirmation depth, events of blocks that are no
+// longer canonical are dropped
+func (sl *SeaportListener) writeConfirmed(db ms.Store) {
+	sl.chain.mu.Lock()
+	head := sl.chain.head
+	confirmed, pending := []interface{}{}, []interface{}{}
+	for _, e := range sl.chain.pending {
+		if eventLog(e).BlockNumber+sl.Confirmations <= head {
+			confirmed = append(confirmed, e)
+		} else {
+			pending = append(pending, e)
+		}
+	}
+	sl.chain.pending = pending
+	sl.chain.mu.Unlock()
+
+	sl.fetchMetadata(context.Background(), eventLogs(confirmed))
+
+	for _, e := range confirmed {
+		l := eventLog(e)
+
+		hash, err := sl.canonicalHash(l.BlockNumber)
+		if err != nil {
+			log.Printf("Failed to get block %d: %v", l.BlockNumber, err.Error())
+			continue
+		}
+
+		if hash != l.BlockHash {
+			log.Printf("Dropping event %s:%d from reorged block %s", l.TxHash.Hex(), l.Index, l.BlockHash.Hex())
+			continue
+		}
+
+		if err := sl.writeEvent(db, e); err != nil {
+			log.Printf("Failed to write confirmed event %s:%d: %v", l.TxHash.Hex(), l.Index, err.Error())
+		}
+	}
+}
+
+// Drops the pending events of a block
+func (sl *SeaportListener) dropPending(blockHash common.Hash) {
+	sl.chain.mu.Lock()
+	defer sl.chain.mu.Unlock()
+
+	pending := sl.chain.pending[:0]
+	for _, e := range sl.chain.pending {
+		if eventLog(e).BlockHash != blockHash {
+			pending = append(pending, e)
+		}
+	}
+	sl.chain.pending = pending
+}
+
+// Rolls back the persisted events after the given block that are not on the canonical chain
+func (sl *SeaportListener) rollbackAfter(db ms.Store, fork uint64) error {
+	blocks, err := db.EventBlocks(context.Background(), sl.ChainID.Int64(), fork+1)
+	if err != nil {
+		return err
+	}
+
+	for number, hashes := range blocks {
+		// Blocks above the head of a shorter chain have no canonical hash
+		canonical, err := sl.canonicalHash(number)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+
+		for _, hash := range hashes {
+			if hash == canonical {
+				continue
+			}
+
+			if err := sl.rollback(db, hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Deletes the events of a block and re-validates the orders whose state they changed
+func (sl *SeaportListener) rollback(db ms.Store, blockHash common.Hash) error {
+	ctx := context.Background()
+
+	r, err := db.RollbackBlock(ctx, sl.ChainID.Int64(), blockHash)
+	if err != nil {
+		log.Printf("Failed to roll back block %s: %v", blockHash.Hex(), err.Error())
+		return err
+	}
+
+	if r.Empty() {
+		return nil
+	}
+
+	log.Printf("Rolled back block %s: %d orders, %d counters", blockHash.Hex(), len(r.Orders), len(r.Offerers))
+
+	if len(sl.Validators) == 0 {
+		log.Printf("No validator, orders affected by block %s keep their status", blockHash.Hex())
+		return nil
+	}
+
+	orders := []*ms.Order{}
+
+	for _, hash := range r.Orders {
+		o, err := db.GetOrder(ctx, sl.ChainID.Int64(), hash)
+		if errors.Is(err, ms.ErrOrderNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		orders = append(orders, o)
+	}
+
+	if len(r.Offerers) > 0 {
+		stale, err := db.StaleOrders(ctx, sl.ChainID.Int64(), r.Offerers)
+		if err != nil {
+			return err
+		}
+
+		orders = append(orders, stale...)
+	}
+
+	for _, o := range orders {
+		v := sl.Validators.ForContract(o.Seaport)
+		if v == nil {
+			continue
+		}
+
+		c := o.Components()
+
+		res, err := v.Validate(ctx, &c, o.Signature)
+		if err != nil {
+			log.Printf("Failed to re-validate order %s: %v", o.Hash.Hex(), err.Error())
+			continue
+		}
+
+		if err := db.UpdateOrderValidation(ctx, o.ChainID, o.Hash, res); err != nil {
+			log.Printf("Failed to update order %s: %v", o.Hash.Hex(), err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	ms "goport/db"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func storedEvents(t *testing.T, s ms.Store) int {
+	t.Helper()
+
+	events, err := s.QueryEvents(context.Background(), ms.EventQuery{ChainID: 1})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+
+	return len(events)
+}
+
+// Hands the last log of the chain to the listener like the subscription does
+func receiveLast(t *testing.T, sl *SeaportListener, s ms.Store, chain *testChain) types.Log {
+	t.Helper()
+
+	l := chain.lastLog()
+	sl.receive(s, l)
+
+	return l
+}
+
+func TestEventsWaitForConfirmations(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 2)
+
+	sl.newHead(store, chain.header(10))
+
+	chain.emit(cancelledLog(sl.Deployments[0].Address, 1))
+	receiveLast(t, sl, store, chain)
+
+	// The event of block 10 is written at block 12
+	for _, want := range []int{0, 0, 1} {
+		if n := storedEvents(t, store); n != want {
+			t.Fatalf("stored %d events at block %d, want %d", n, sl.chain.head, want)
+		}
+
+		sl.newHead(store, chain.header(chain.mine(1)))
+	}
+}
+
+func TestRemovedLogRollsBack(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+
+	chain.emit(cancelledLog(sl.Deployments[0].Address, 1))
+	l := receiveLast(t, sl, store, chain)
+
+	if n := storedEvents(t, store); n != 1 {
+		t.Fatalf("stored %d events, want 1", n)
+	}
+
+	l.Removed = true
+	sl.receive(store, l)
+
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("%d events left after the log was removed, want 0", n)
+	}
+}
+
+func TestReorgRollsBack(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+
+	sl.newHead(store, chain.header(10))
+
+	chain.emit(cancelledLog(sl.Deployments[0].Address, 1))
+	receiveLast(t, sl, store, chain)
+
+	// Another block 10 on top of the same parent replaces the block of the event
+	fork := types.CopyHeader(chain.header(10))
+	fork.Time++
+
+	if number, reorged := sl.newHead(store, fork); !reorged || number != 9 {
+		t.Fatalf("newHead of a sibling block = %d, %v, want 9, true", number, reorged)
+	}
+
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("%d events left after the reorg, want 0", n)
+	}
+
+	// The new chain continuing is not a reorg
+	next := &types.Header{ParentHash: fork.Hash(), Number: big.NewInt(11), Difficulty: new(big.Int)}
+	if _, reorged := sl.newHead(store, next); reorged {
+		t.Fatal("newHead of a child of the head reorged")
+	}
+}
+
+func TestTrackHeadFetchesWithoutLock(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 2)
+
+	for n := uint64(8); n <= 10; n++ {
+		sl.newHead(store, chain.header(n))
+	}
+
+	chain.reorg(2)
+
+	var fetched, locked int32
+	chain.mu.Lock()
+	chain.onBlockByHash = func() {
+		atomic.AddInt32(&fetched, 1)
+		if !sl.chain.mu.TryLock() {
+			atomic.AddInt32(&locked, 1)
+			return
+		}
+		sl.chain.mu.Unlock()
+	}
+	chain.mu.Unlock()
+
+	if fork, reorged := sl.newHead(store, chain.header(10)); !reorged || fork != 8 {
+		t.Fatalf("newHead of a new branch = %d, %v, want 8, true", fork, reorged)
+	}
+
+	if atomic.LoadInt32(&fetched) == 0 {
+		t.Fatal("the blocks of the new branch were not fetched")
+	}
+	if n := atomic.LoadInt32(&locked); n != 0 {
+		t.Fatalf("%d blocks were fetched holding the tracker lock", n)
+	}
+}
+
+func TestVerifyHeadsAfterReconnect(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+
+	sl.newHead(store, chain.header(10))
+	chain.emit(cancelledLog(sl.Deployments[0].Address, 1))
+	receiveLast(t, sl, store, chain)
+
+	// Nothing changed while connected
+	if err := sl.verifyHeads(context.Background(), store); err != nil {
+		t.Fatalf("verifyHeads: %v", err)
+	}
+	if n := storedEvents(t, store); n != 1 {
+		t.Fatalf("stored %d events, want 1", n)
+	}
+
+	// Block 10 was replaced while the listener was disconnected
+	chain.reorg(1)
+
+	if err := sl.verifyHeads(context.Background(), store); err != nil {
+		t.Fatalf("verifyHeads: %v", err)
+	}
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("%d events of the reorged block left, want 0", n)
+	}
+
+	// The new block 10 is not a reorg when its head arrives
+	if _, reorged := sl.newHead(store, chain.header(10)); reorged {
+		t.Fatal("newHead of the verified head reorged")
+	}
+}
@@ -2,187 +2,317 @@ package listener
 
 import (
 	"context"
-	"goport/abi"
+	"errors"
 	"goport/config"
 	ms "goport/db"
+	"goport/order"
 	"log"
+	"math/big"
 	"sync"
+	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+var ErrWrongChain = errors.New("RPC endpoint is on another chain")
+
+// Follows the Seaport deployments of a chain
 type SeaportListener struct {
-	Seaport             *abi.Seaport
-	WatchCountInc       chan *abi.SeaportCounterIncremented
-	WatchOrderCancelled chan *abi.SeaportOrderCancelled
-	WatchOrderValidated chan *abi.SeaportOrderValidated
-	WatchOrderFulfilled chan *abi.SeaportOrderFulfilled
+	// Name of the chain in the configuration
+	Name    string
+	Client  *ethclient.Client
+	ChainID *big.Int
+
+	// Connection of Client, for batch requests
+	rpcClient *rpc.Client
+
+	// Seaport deployments followed on the chain
+	Deployments []*Deployment
+
+	// Number of blocks an event waits for before it is written
+	Confirmations uint64
+
+	// Re-validate the orders affected by rolled back events
+	Validators order.Validators
+
+	chain *chainTracker
+
+	// Block timestamps and transaction senders of recent events
+	metadata *metadataCache
+
+	// Follow the chain by polling eth_getLogs instead of subscribing
+	Polling        bool
+	PollInterval   time.Duration
+	PollBlockRange uint64
+
+	// Last block received, last block filled after re-subscribing and last head polled, only used
+	// by the goroutine following the chain
+	lastBlock uint64
+	filledTo  uint64
+	lastHead  uint64
 }
 
-// Creates a new SeaportListener
-func New() (*SeaportListener, error) {
-	c, err := ethclient.Dial(config.RPC_URL)
+// Backoff between attempts to re-subscribe
+const (
+	minBackoff = time.Second
+	maxBackoff = 2 * time.Minute
+)
+
+// Creates a new SeaportListener for a chain
+func New(chain config.Chain) (*SeaportListener, error) {
+	rc, err := rpc.Dial(chain.RPCURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to the Ethereum client: %v", err.Error())
 		return nil, err
 	}
+	c := ethclient.NewClient(rc)
 
-	s, err := abi.NewSeaport(common.HexToAddress("0x00000000006c3852cbEf3e08E8dF289169EdE581"), c)
+	id, err := c.ChainID(context.Background())
 	if err != nil {
-		log.Printf("Failed to create new Seaport: %v", err.Error())
+		log.Printf("Failed to get chain id: %v", err.Error())
 		return nil, err
 	}
 
+	if chain.ChainID != 0 && id.Uint64() != chain.ChainID {
+		log.Printf("RPC endpoint of chain %s is on chain %s, expected %d", chain.Name, id.String(), chain.ChainID)
+		return nil, ErrWrongChain
+	}
+
+	polling, err := usePolling(chain.RPCURL, chain.RPCMode)
+	if err != nil {
+		log.Printf("Failed to select RPC mode: %v", err.Error())
+		return nil, err
+	}
+
+	deployments := []*Deployment{}
+	for _, entry := range chain.SeaportDeployments {
+		d, err := NewDeployment(entry, id, c)
+		if err != nil {
+			log.Printf("Failed to create Seaport deployment %s: %v", entry, err.Error())
+			return nil, err
+		}
+
+		deployments = append(deployments, d)
+	}
+
 	return &SeaportListener{
-		Seaport:       s,
-		WatchCountInc: make(chan *abi.SeaportCounterIncremented),
+		Client:         c,
+		rpcClient:      rc,
+		ChainID:        id,
+		Deployments:    deployments,
+		Name:           chain.Name,
+		Confirmations:  chain.Confirmations,
+		Polling:        polling,
+		PollInterval:   config.POLL_INTERVAL,
+		PollBlockRange: config.POLL_BLOCK_RANGE,
+		chain:          &chainTracker{headers: make(map[uint64]common.Hash)},
+		metadata:       newMetadataCache(),
 	}, nil
 }
 
-func (sl *SeaportListener) Start(wg *sync.WaitGroup, db *ms.SQLWrapper) {
-	sl.watchCounterIncremented(wg, db)
-	sl.watchOrderCancelled(wg, db)
-	sl.watchOrderValidated(wg, db)
-	sl.watchOrderFulfilled(wg, db)
+// Returns the last block with the configured number of confirmations
+func (sl *SeaportListener) ConfirmedHead(ctx context.Context) (uint64, error) {
+	head, err := sl.Client.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if head < sl.Confirmations {
+		return 0, nil
+	}
+
+	return head - sl.Confirmations, nil
+}
+
+// Makes the listener handle the events after the given block when it starts, the blocks up to it
+// are left to the backfill. Must be called before Start.
+func (sl *SeaportListener) ResumeAfter(block uint64) {
+	sl.lastBlock = block
 }
 
-// Watches the Seaport contract for a counter incremented event and writes it to the database
-func (sl *SeaportListener) watchCounterIncremented(wg *sync.WaitGroup, db *ms.SQLWrapper) {
+// Follows the Seaport contract in the background, by subscribing or polling
+func (sl *SeaportListener) Start(wg *sync.WaitGroup, db ms.Store) {
 	wg.Add(1)
+
 	go func() {
-		sub, err := sl.Seaport.WatchCounterIncremented(&bind.WatchOpts{Context: context.Background()}, sl.WatchCountInc, []common.Address{})
-		if err != nil {
-			log.Printf("Failed to watch CounterIncremented: %v", err.Error())
+		defer wg.Done()
+
+		if sl.Polling {
+			sl.poll(db)
 			return
 		}
 
-		for {
-			if err := <-sub.Err(); err != nil {
-				log.Printf("Failed to watch CounterIncremented: %v", err.Error())
-				break
-			}
+		sl.supervise(db)
+	}()
+}
 
-			e := <-sl.WatchCountInc
+// Re-subscribes with exponential backoff whenever a subscription fails. The RPC client redials the
+// endpoint on the next request, so re-subscribing also reconnects it.
+func (sl *SeaportListener) supervise(db ms.Store) {
+	backoff := minBackoff
 
-			db.Lock()
+	for {
+		started := time.Now()
 
-			err = db.WriteCounterIncremented(e)
-			if err != nil {
-				log.Printf("Failed to write CounterIncremented: %v", err.Error())
-				break
-			}
+		err := sl.listen(db)
+		log.Printf("Seaport subscription on %s failed: %v", sl.Name, err)
 
-			db.Unlock()
-			log.Printf("CounterIncremented: %v", e.Raw.Address.String())
+		// A connection that held up for a while starts over with a short backoff
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
 		}
 
-		wg.Done()
-	}()
+		log.Printf("Re-subscribing to Seaport events on %s in %v", sl.Name, backoff)
+		time.Sleep(backoff)
 
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
 }
 
-// Watches the Seaport contract for a order cancelled event and writes it to the database
-func (sl *SeaportListener) watchOrderCancelled(wg *sync.WaitGroup, db *ms.SQLWrapper) {
-	wg.Add(1)
-	go func() {
-		sub, err := sl.Seaport.WatchOrderCancelled(&bind.WatchOpts{Context: context.Background()}, sl.WatchOrderCancelled, []common.Address{}, []common.Address{})
-		if err != nil {
-			log.Printf("Failed to watch OrderCancelled: %v", err.Error())
-			return
-		}
+// Subscribes to new heads and the Seaport logs, fills the blocks missed since the last
+// subscription, and handles events until a subscription fails
+func (sl *SeaportListener) listen(db ms.Store) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-		for {
-			if err := <-sub.Err(); err != nil {
-				log.Printf("Failed to watch OrderCancelled: %v", err.Error())
-				break
-			}
+	heads := make(chan *types.Header)
 
-			e := <-sl.WatchOrderCancelled
+	headSub, err := sl.Client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
 
-			db.Lock()
+	// A single subscription keeps events of different types in the order they were emitted
+	logs := make(chan types.Log)
 
-			err := db.WriteOrderCancelled(e)
-			if err != nil {
-				log.Printf("Failed to write OrderCancelled: %v", err.Error())
-				break
-			}
+	logSub, err := sl.Client.SubscribeFilterLogs(ctx, query(sl.addresses()), logs)
+	if err != nil {
+		return err
+	}
+	defer logSub.Unsubscribe()
 
-			db.Unlock()
-			log.Printf("OrderCancelled: %v", e.Raw.Address.String())
-		}
+	// Blocks that were not confirmed yet may have been reorged out while disconnected
+	if err := sl.verifyHeads(ctx, db); err != nil {
+		return err
+	}
 
-		wg.Done()
-	}()
-}
+	// The subscriptions buffer new logs while the gap is filled
+	if err := sl.fillGap(ctx, db); err != nil {
+		return err
+	}
 
-// Watches the Seaport contract for a order validated event and writes it to the database
-func (sl *SeaportListener) watchOrderValidated(wg *sync.WaitGroup, db *ms.SQLWrapper) {
-	wg.Add(1)
-	go func() {
-		sub, err := sl.Seaport.WatchOrderValidated(&bind.WatchOpts{Context: context.Background()}, sl.WatchOrderValidated, []common.Address{}, []common.Address{})
-		if err != nil {
-			log.Printf("Failed to watch OrderValidated: %v", err.Error())
-			return
+	for {
+		select {
+		case err := <-headSub.Err():
+			return err
+		case err := <-logSub.Err():
+			return err
+
+		case h := <-heads:
+			sl.newHead(db, h)
+			sl.seen(h.Number.Uint64())
+
+		case l := <-logs:
+			sl.receive(db, l)
 		}
+	}
+}
 
-		for {
-			if err := <-sub.Err(); err != nil {
-				log.Printf("Failed to watch OrderValidated: %v", err.Error())
-				break
-			}
+// Handles a log from the subscription
+func (sl *SeaportListener) receive(db ms.Store, l types.Log) {
+	// Logs of filled blocks are delivered again by a subscription created before the fill
+	if !l.Removed && l.BlockNumber <= sl.filledTo {
+		return
+	}
 
-			e := <-sl.WatchOrderValidated
+	event, err := sl.decodeLog(l)
+	if errors.Is(err, ErrUnknownEvent) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to decode Seaport log %s:%d: %v", l.TxHash.Hex(), l.Index, err.Error())
+		return
+	}
 
-			db.Lock()
+	if err := sl.handleEvent(db, event); err != nil {
+		log.Printf("Failed to write %s: %v", eventName(event), err.Error())
+		return
+	}
 
-			err := db.WriteOrderValidated(e)
-			if err != nil {
-				log.Printf("Failed to write OrderValidated: %v", err.Error())
-				break
-			}
+	if !l.Removed {
+		sl.seen(l.BlockNumber)
+	}
 
-			db.Unlock()
-			log.Printf("OrderValidated: %v", e.Raw.Address.String())
-		}
+	log.Printf("%s on %s: %v", eventName(event), sl.Name, l.TxHash.Hex())
+}
 
-		wg.Done()
-	}()
+// Records the last block the listener received
+func (sl *SeaportListener) seen(block uint64) {
+	if block > sl.lastBlock {
+		sl.lastBlock = block
+	}
 }
 
-// Watches the Seaport contract for a order fulfilled event and writes it to the database
-func (sl *SeaportListener) watchOrderFulfilled(wg *sync.WaitGroup, db *ms.SQLWrapper) {
-	wg.Add(1)
+// Handles the events emitted between the last received block and the current head
+func (sl *SeaportListener) fillGap(ctx context.Context, db ms.Store) error {
+	// Nothing was received yet and there is no backfill to continue from
+	if sl.lastBlock == 0 {
+		return nil
+	}
 
-	go func() {
-		sub, err := sl.Seaport.WatchOrderFulfilled(&bind.WatchOpts{Context: context.Background()}, sl.WatchOrderFulfilled, []common.Address{}, []common.Address{})
-		if err != nil {
-			log.Printf("Failed to watch OrderFulfilled: %v", err.Error())
-			return
-		}
+	head, err := sl.Client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
 
-		for {
-			if err := <-sub.Err(); err != nil {
-				log.Printf("Failed to watch OrderFulfilled: %v", err.Error())
-				break
-			}
+	n, err := sl.handleRange(ctx, db, sl.lastBlock+1, head, config.BACKFILL_CHUNK_SIZE)
+	if err != nil {
+		return err
+	}
+
+	sl.filledTo = head
+	if n > 0 {
+		log.Printf("Filled %s blocks missed while disconnected up to %d: %d events", sl.Name, head, n)
+	}
 
-			e := <-sl.WatchOrderFulfilled
+	return nil
+}
 
-			db.Lock()
+// Handles the events of a block range, in chunks of at most chunkSize blocks
+func (sl *SeaportListener) handleRange(ctx context.Context, db ms.Store, start uint64, end uint64, chunkSize uint64) (int, error) {
+	count := 0
 
-			err := db.WriteOrderFulfilled(e)
-			if err != nil {
-				log.Printf("Failed to write OrderFulfilled: %v", err.Error())
-				break
-			}
+	for ; start <= end; start += chunkSize {
+		last := start + chunkSize - 1
+		if last > end {
+			last = end
+		}
+
+		events, err := sl.filterEvents(ctx, sl.addresses(), start, last)
+		if err != nil {
+			return count, err
+		}
 
-			db.Unlock()
-			log.Printf("OrderFulfilled: %v", e.Raw.Address.String())
+		if sl.Confirmations == 0 {
+			sl.fetchMetadata(ctx, eventLogs(events))
+		}
 
+		for _, e := range events {
+			if err := sl.handleEvent(db, e); err != nil {
+				return count, err
+			}
 		}
 
-		wg.Done()
-	}()
+		count += len(events)
+		sl.seen(last)
+	}
+
+	return count, nil
 }
@@ -0,0 +1,54 @@
+package listener
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ms "goport/db"
+)
+
+func TestListenerFillsGapAfterReconnect(t *testing.T) {
+	chain := newTestChain(t, 10)
+	store := ms.NewMemoryStore()
+	sl := newTestListener(t, chain, 0)
+	seaport := sl.Deployments[0].Address
+
+	sl.Start(&sync.WaitGroup{}, store)
+	waitFor(t, chain.subbed, "the Seaport subscription")
+
+	chain.emit(cancelledLog(seaport, 1))
+	if blocks := eventBlocks(t, store, 1); len(blocks) != 1 || blocks[0] != 10 {
+		t.Fatalf("stored events of blocks %v, want [10]", blocks)
+	}
+
+	// Events emitted while the connection is down are only found by filling the gap
+	chain.drop()
+	chain.mine(1)
+	chain.emit(cancelledLog(seaport, 2))
+	chain.mine(2)
+
+	waitFor(t, chain.subbed, "the Seaport subscription to be renewed")
+
+	if blocks := eventBlocks(t, store, 2); len(blocks) != 2 || blocks[1] != 11 {
+		t.Fatalf("stored events of blocks %v, want [10 11]", blocks)
+	}
+
+	// The gap starts after the last block received and ends at the head when re-subscribing
+	if ranges := chain.filtered(); len(ranges) != 1 || ranges[0] != [2]uint64{11, 13} {
+		t.Fatalf("filled the gap with eth_getLogs over %v, want [[11 13]]", ranges)
+	}
+
+	// The renewed subscription delivers new events, without the filled ones again
+	chain.mine(1)
+	chain.emit(cancelledLog(seaport, 3))
+
+	if blocks := eventBlocks(t, store, 3); len(blocks) != 3 || blocks[2] != 14 {
+		t.Fatalf("stored events of blocks %v, want [10 11 14]", blocks)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := storedEvents(t, store); n != 3 {
+		t.Fatalf("stored %d events, want 3", n)
+	}
+}
@@ -0,0 +1,392 @@
+package listener
+
+import (
+	"context"
+	"goport/abi"
+	ms "goport/db"
+	"goport/order"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// How often the set of watched tokens is refreshed from the orders in the database
+const tokenRefreshInterval = time.Minute
+
+var (
+	transferTopic       = mustEventID(abi.ERC20MetaData, "Transfer")
+	approvalTopic       = mustEventID(abi.ERC20MetaData, "Approval")
+	transferSingleTopic = mustEventID(abi.ERC1155MetaData, "TransferSingle")
+	transferBatchTopic  = mustEventID(abi.ERC1155MetaData, "TransferBatch")
+	approvalForAllTopic = mustEventID(abi.ERC721MetaData, "ApprovalForAll")
+)
+
+// ERC20 and ERC721 Transfer and Approval events share their signature, so their topics are
+// the same and only the number of indexed arguments tells them apart
+var tokenTopics = [][]common.Hash{{transferTopic, approvalTopic, transferSingleTopic, transferBatchTopic, approvalForAllTopic}}
+
+func mustEventID(m *bind.MetaData, name string) common.Hash {
+	a, err := m.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+
+	return a.Events[name].ID
+}
+
+// Watches the tokens offered by stored orders for transfers and approval changes, and re-validates
+// the orders of the accounts involved
+func (sl *SeaportListener) WatchTokens(wg *sync.WaitGroup, db ms.Store, vs order.Validators) {
+	if sl.Polling {
+		sl.pollTokens(wg, db, vs)
+		return
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		w := &tokenWatcher{sl: sl, db: db, vs: vs}
+		w.supervise()
+	}()
+}
+
+// Follows the token events over a log subscription
+type tokenWatcher struct {
+	sl *SeaportListener
+	db ms.Store
+	vs order.Validators
+
+	// Tokens of the current subscription, sorted
+	tokens []common.Address
+
+	// Last block whose token events were handled, and last block caught up with FilterLogs. A quiet
+	// subscription does not move lastBlock, the catch up after it fails starts at its last event.
+	lastBlock uint64
+	filledTo  uint64
+}
+
+// Re-subscribes with exponential backoff whenever the subscription fails, like supervise
+func (w *tokenWatcher) supervise() {
+	backoff := minBackoff
+
+	for {
+		started := time.Now()
+
+		err := w.watch()
+		log.Printf("Token event subscription on %s failed: %v", w.sl.Name, err)
+
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+
+		log.Printf("Re-subscribing to token events on %s in %v", w.sl.Name, backoff)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// Subscribes to the events of the offered tokens, catches up on the blocks missed since the last
+// subscription and handles events until the subscription fails. The subscription is replaced when
+// the offered tokens change.
+func (w *tokenWatcher) watch() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		sub  ethereum.Subscription
+		errs <-chan error
+	)
+
+	defer func() {
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	}()
+
+	logs := make(chan types.Log)
+	ticker := time.NewTicker(tokenRefreshInterval)
+	defer ticker.Stop()
+
+	refresh := func(first bool) error {
+		tokens, err := w.db.OfferTokens(ctx, w.sl.ChainID.Int64())
+		if err != nil {
+			log.Printf("Failed to read offered tokens: %v", err.Error())
+			if first {
+				return err
+			}
+			// Keeps the current subscription, the tokens are read again on the next tick
+			return nil
+		}
+
+		sort.Slice(tokens, func(i, j int) bool { return tokens[i].Hex() < tokens[j].Hex() })
+		if !first && equalAddresses(tokens, w.tokens) {
+			return nil
+		}
+
+		// A filter without addresses would match the events of every token
+		var next ethereum.Subscription
+		if len(tokens) > 0 {
+			if next, err = w.sl.Client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{
+				Addresses: tokens,
+				Topics:    tokenTopics,
+			}, logs); err != nil {
+				return err
+			}
+		}
+
+		// The old subscription ends after the new one started, so changing the tokens leaves no gap.
+		// Events delivered by both are handled twice, re-validating is idempotent.
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+		sub, errs = nil, nil
+		w.tokens = tokens
+
+		if next == nil {
+			// Orders stored later are validated when they are stored, there is no gap to fill
+			w.lastBlock = 0
+			return nil
+		}
+		sub, errs = next, next.Err()
+
+		log.Printf("Watching %d tokens for transfers and approvals", len(tokens))
+
+		if !first && w.lastBlock != 0 {
+			return nil
+		}
+
+		// The subscription buffers new logs while the gap is filled
+		return w.fillGap(ctx)
+	}
+
+	if err := refresh(true); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := refresh(false); err != nil {
+				return err
+			}
+
+		case err := <-errs:
+			return err
+
+		case l := <-logs:
+			// Logs of filled blocks are delivered again by a subscription created before the fill
+			if !l.Removed && l.BlockNumber <= w.filledTo {
+				continue
+			}
+
+			w.sl.revalidate(w.db, w.vs, l)
+
+			if l.BlockNumber > w.lastBlock {
+				w.lastBlock = l.BlockNumber
+			}
+		}
+	}
+}
+
+// Handles the token events emitted between the last handled block and the current head
+func (w *tokenWatcher) fillGap(ctx context.Context) error {
+	head, err := w.sl.Client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Nothing was handled yet, the orders were validated when they were stored
+	if w.lastBlock == 0 {
+		w.lastBlock = head
+		return nil
+	}
+
+	if head > w.lastBlock {
+		last, err := w.sl.handleTokenRange(ctx, w.db, w.vs, w.tokens, w.lastBlock+1, head)
+		w.lastBlock = last
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Caught up on token events of %s up to %d", w.sl.Name, head)
+	}
+
+	w.lastBlock = head
+	w.filledTo = head
+
+	return nil
+}
+
+// Polls the tokens offered by stored orders for transfers and approval changes
+func (sl *SeaportListener) pollTokens(wg *sync.WaitGroup, db ms.Store, vs order.Validators) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		var last uint64
+
+		ticker := time.NewTicker(sl.PollInterval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			ctx := context.Background()
+
+			head, err := sl.Client.BlockNumber(ctx)
+			if err != nil {
+				log.Printf("Failed to poll token events: %v", err.Error())
+				continue
+			}
+
+			if last == 0 {
+				last = head
+				continue
+			}
+
+			tokens, err := db.OfferTokens(ctx, sl.ChainID.Int64())
+			if err != nil {
+				log.Printf("Failed to read offered tokens: %v", err.Error())
+				continue
+			}
+
+			if len(tokens) == 0 {
+				last = head
+				continue
+			}
+
+			if last, err = sl.handleTokenRange(ctx, db, vs, tokens, last+1, head); err != nil {
+				log.Printf("Failed to poll token events: %v", err.Error())
+			}
+		}
+	}()
+}
+
+// Re-validates the orders affected by the token events of a block range, in chunks of at most
+// PollBlockRange blocks. Returns the last block handled, which is start-1 if the first chunk failed.
+func (sl *SeaportListener) handleTokenRange(ctx context.Context, db ms.Store, vs order.Validators, tokens []common.Address, start uint64, end uint64) (uint64, error) {
+	last := start - 1
+
+	for ; start <= end; start += sl.PollBlockRange {
+		chunkEnd := start + sl.PollBlockRange - 1
+		if chunkEnd > end {
+			chunkEnd = end
+		}
+
+		logs, err := sl.Client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(chunkEnd),
+			Addresses: tokens,
+			Topics:    tokenTopics,
+		})
+		if err != nil {
+			return last, err
+		}
+
+		for _, l := range logs {
+			sl.revalidate(db, vs, l)
+		}
+
+		last = chunkEnd
+	}
+
+	return last, nil
+}
+
+// Re-validates the orders affected by a token event and updates their status
+func (sl *SeaportListener) revalidate(db ms.Store, vs order.Validators, l types.Log) {
+	accounts := affectedAccounts(l)
+	if len(accounts) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+
+	orders, err := db.OrdersByOfferToken(ctx, sl.ChainID.Int64(), accounts, l.Address)
+	if err != nil {
+		log.Printf("Failed to read orders affected by %s: %v", l.TxHash.Hex(), err.Error())
+		return
+	}
+
+	for _, o := range orders {
+		v := vs.ForContract(o.Seaport)
+		if v == nil {
+			continue
+		}
+
+		c := o.Components()
+
+		res, err := v.Validate(ctx, &c, o.Signature)
+		if err != nil {
+			log.Printf("Failed to re-validate order %s: %v", o.Hash.Hex(), err.Error())
+			continue
+		}
+
+		status := ms.StatusFromResult(res)
+		if status == o.Status {
+			continue
+		}
+
+		if err := db.UpdateOrderStatus(ctx, o.ChainID, o.Hash, status); err != nil {
+			log.Printf("Failed to update status of order %s: %v", o.Hash.Hex(), err.Error())
+			continue
+		}
+
+		log.Printf("Order %s is now %s", o.Hash.Hex(), status)
+	}
+}
+
+// Returns the accounts whose balance or approvals a token event changed
+func affectedAccounts(l types.Log) []common.Address {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+
+	account := func(i int) common.Address {
+		return common.BytesToAddress(l.Topics[i].Bytes())
+	}
+
+	switch l.Topics[0] {
+	case transferTopic:
+		// The receiver is included, a transfer back can make a flagged order valid again
+		if len(l.Topics) >= 3 {
+			return []common.Address{account(1), account(2)}
+		}
+	case transferSingleTopic, transferBatchTopic:
+		if len(l.Topics) == 4 {
+			return []common.Address{account(2), account(3)}
+		}
+	case approvalTopic, approvalForAllTopic:
+		if len(l.Topics) >= 2 {
+			return []common.Address{account(1)}
+		}
+	}
+
+	return nil
+}
+
+func equalAddresses(a []common.Address, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,103 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	ms "goport/db"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Store offering a single token, recording the accounts whose orders are re-validated
+type tokenStore struct {
+	*ms.MemoryStore
+	token    common.Address
+	affected chan []common.Address
+}
+
+func (s *tokenStore) OfferTokens(ctx context.Context, chainID int64) ([]common.Address, error) {
+	return []common.Address{s.token}, nil
+}
+
+func (s *tokenStore) OrdersByOfferToken(ctx context.Context, chainID int64, offerers []common.Address, token common.Address) ([]*ms.Order, error) {
+	s.affected <- offerers
+	return nil, nil
+}
+
+func transferLog(token common.Address, from common.Address, to common.Address) types.Log {
+	return types.Log{
+		Address: token,
+		Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(1))},
+	}
+}
+
+func waitFor(t *testing.T, c <-chan struct{}, what string) {
+	t.Helper()
+
+	select {
+	case <-c:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func waitAffected(t *testing.T, s *tokenStore, want common.Address) {
+	t.Helper()
+
+	select {
+	case accounts := <-s.affected:
+		if len(accounts) == 0 || accounts[0] != want {
+			t.Fatalf("re-validated the orders of %v, want %s", accounts, want.Hex())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("orders of %s were not re-validated", want.Hex())
+	}
+}
+
+func TestWatchTokensCatchesUpAfterReconnect(t *testing.T) {
+	chain := newTestChain(t, 100)
+
+	token := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	carol := common.HexToAddress("0x00000000000000000000000000000000000ca201")
+
+	store := &tokenStore{MemoryStore: ms.NewMemoryStore(), token: token, affected: make(chan []common.Address, 16)}
+	sl := &SeaportListener{Name: "test", Client: chain.client(), ChainID: big.NewInt(1), PollBlockRange: 2}
+
+	w := &tokenWatcher{sl: sl, db: store}
+	go w.supervise()
+
+	waitFor(t, chain.subbed, "the token subscription")
+
+	chain.mine(1)
+	chain.emit(transferLog(token, alice, bob))
+	waitAffected(t, store, alice)
+
+	// Events emitted while the connection is down are only found by the catch up
+	chain.drop()
+	chain.mine(1)
+	chain.emit(transferLog(token, carol, bob))
+	chain.mine(3)
+
+	waitFor(t, chain.subbed, "the token subscription to be renewed")
+	waitAffected(t, store, carol)
+
+	// The catch up starts after the last event handled, in chunks of PollBlockRange blocks
+	ranges := chain.filtered()
+	for deadline := time.Now().Add(10 * time.Second); len(ranges) < 2 && time.Now().Before(deadline); ranges = chain.filtered() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(ranges) != 2 || ranges[0] != [2]uint64{102, 103} || ranges[1] != [2]uint64{104, 105} {
+		t.Fatalf("caught up with eth_getLogs over %v, want [[102 103] [104 105]]", ranges)
+	}
+
+	// The subscription delivers new events again
+	chain.mine(1)
+	chain.emit(transferLog(token, bob, alice))
+	waitAffected(t, store, bob)
+}
@@ -0,0 +1,90 @@
+package node
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-msgio"
+)
+
+// Retrieves orders for a collection from a peer
+func (n *Node) GetOrders(ctx context.Context, p peer.ID, collection string, opts GetOrdersOpts) ([]OrderJSON, error) {
+	res := &Orders{}
+	if err := n.request(ctx, p, &GetOrders{ReqID: n.nextReqID(), Collection: collection, Opts: opts}, res); err != nil {
+		return nil, err
+	}
+
+	return res.Orders, nil
+}
+
+// Retrieves the hashes of the orders for a collection from a peer
+func (n *Node) GetOrderHashes(ctx context.Context, p peer.ID, collection string, opts GetOrdersOpts) ([]string, error) {
+	res := &OrderHashes{}
+	if err := n.request(ctx, p, &GetOrderHashes{ReqID: n.nextReqID(), Collection: collection, Opts: opts}, res); err != nil {
+		return nil, err
+	}
+
+	return res.Hashes, nil
+}
+
+// Retrieves the number of orders a peer has for a collection
+func (n *Node) GetOrderCount(ctx context.Context, p peer.ID, collection string, opts GetOrdersOpts) (uint32, error) {
+	res := &OrderCount{}
+	if err := n.request(ctx, p, &GetOrderCount{ReqID: n.nextReqID(), Collection: collection, Opts: opts}, res); err != nil {
+		return 0, err
+	}
+
+	return res.Count, nil
+}
+
+// Retrieves the token ids included in a criteria merkle root from a peer
+func (n *Node) GetCriteria(ctx context.Context, p peer.ID, hash string) ([]string, error) {
+	res := &Criteria{}
+	if err := n.request(ctx, p, &GetCriteria{ReqID: n.nextReqID(), Hash: hash}, res); err != nil {
+		return nil, err
+	}
+
+	return res.TokenIDs, nil
+}
+
+func (n *Node) nextReqID() uint32 {
+	return atomic.AddUint32(&n.reqID, 1)
+}
+
+// Opens a new stream to the peer, writes the request and decodes the response into res
+func (n *Node) request(ctx context.Context, p peer.ID, req message, res message) error {
+	s, err := n.Host.NewStream(ctx, p, ProtocolID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(streamTimeout)
+	}
+
+	if err := s.SetDeadline(deadline); err != nil {
+		s.Reset()
+		return err
+	}
+
+	if err := writeMessage(msgio.NewVarintWriter(s), req); err != nil {
+		s.Reset()
+		return err
+	}
+
+	if err := s.CloseWrite(); err != nil {
+		s.Reset()
+		return err
+	}
+
+	if err := readResponse(msgio.NewVarintReaderSize(s, maxMessageSize), req, res); err != nil {
+		s.Reset()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"goport/db"
+	"goport/order"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Time allowed to fetch the token ids of a criteria root from a peer
+const criteriaTimeout = 30 * time.Second
+
+// Stores the token ids of a criteria item so they can be served to other nodes, returns their root
+func (n *Node) AddCriteria(ctx context.Context, tokenIDs []*big.Int) (common.Hash, error) {
+	root := order.CriteriaRoot(tokenIDs)
+
+	if err := n.Store.PutCriteria(ctx, root, tokenIDs); err != nil {
+		return common.Hash{}, err
+	}
+
+	return root, nil
+}
+
+// Fetches the token ids of the criteria roots of an order that are not stored yet from the peers that
+// sent it, roots the peers do not know are left unresolved
+func (n *Node) fetchCriteria(o *db.Order, peers ...peer.ID) {
+	for _, root := range criteriaRoots(o) {
+		_, err := n.Store.GetCriteria(context.Background(), root)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, db.ErrCriteriaNotFound) {
+			log.Printf("Failed to get criteria %s: %v", root.Hex(), err.Error())
+			continue
+		}
+
+		for _, p := range peers {
+			if err := n.fetchCriteriaFrom(p, root); err != nil {
+				log.Printf("Failed to fetch criteria %s from %v: %v", root.Hex(), p, err.Error())
+				continue
+			}
+
+			break
+		}
+	}
+}
+
+func (n *Node) fetchCriteriaFrom(p peer.ID, root common.Hash) error {
+	ctx, cancel := context.WithTimeout(context.Background(), criteriaTimeout)
+	defer cancel()
+
+	ids, err := n.GetCriteria(ctx, p, root.Hex())
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return db.ErrCriteriaNotFound
+	}
+
+	tokenIDs := make([]*big.Int, len(ids))
+	for i, id := range ids {
+		if tokenIDs[i], err = parseBig(id); err != nil {
+			return err
+		}
+	}
+
+	return n.Store.PutCriteria(ctx, root, tokenIDs)
+}
+
+// Returns the distinct criteria roots of the items of an order, zero roots match any token and have none
+func criteriaRoots(o *db.Order) []common.Hash {
+	seen := make(map[common.Hash]bool)
+	roots := []common.Hash{}
+
+	add := func(itemType uint8, criteria *db.Uint256) {
+		root := common.BigToHash(criteria.Int())
+		if !abi.IsCriteria(itemType) || root == (common.Hash{}) || seen[root] {
+			return
+		}
+
+		seen[root] = true
+		roots = append(roots, root)
+	}
+
+	for _, item := range o.Offer {
+		add(item.ItemType, item.IdentifierOrCriteria)
+	}
+
+	for _, item := range o.Consideration {
+		add(item.ItemType, item.IdentifierOrCriteria)
+	}
+
+	return roots
+}
@@ -0,0 +1,270 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goport/abi"
+	"goport/bus"
+	"goport/db"
+	"goport/listener"
+	"goport/order"
+	"log"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var ErrWrongChain = errors.New("order was signed for another chain")
+
+// Writes the orders accepted by the topic validator to the database, publishes them on the bus and
+// fetches their criteria from the peers that sent them
+func (n *Node) handleSub(sub *pubsub.Subscription) {
+	for {
+		msg, err := sub.Next(context.Background())
+		if err != nil {
+			log.Printf("Subscription to %s closed: %v", sub.Topic(), err.Error())
+			sub.Cancel()
+			return
+		}
+
+		// Orders published by this node were stored when they were submitted, copies received on
+		// another topic were already handled
+		o, ok := msg.ValidatorData.(*db.Order)
+		if !ok || msg.ReceivedFrom == n.Host.ID() || !n.received.add(o.ChainID, o.Hash) {
+			continue
+		}
+
+		if err := n.Store.PutOrder(context.Background(), o); err != nil {
+			log.Printf("Failed to save order %s to the database: %v", o.Hash.Hex(), err.Error())
+			continue
+		}
+
+		n.Bus.Publish(bus.NewOrderMessage(o))
+
+		if len(criteriaRoots(o)) > 0 {
+			peers := []peer.ID{msg.ReceivedFrom}
+			if author := msg.GetFrom(); author != msg.ReceivedFrom {
+				peers = append(peers, author)
+			}
+
+			go n.fetchCriteria(o, peers...)
+		}
+	}
+}
+
+// Number of gossiped order hashes remembered to drop the copies received on another topic
+const recentOrdersSize = 4096
+
+type recentOrder struct {
+	chainID int64
+	hash    common.Hash
+}
+
+// Remembers the orders received recently, the zero value is ready to use
+type recentOrders struct {
+	mu sync.Mutex
+
+	seen map[recentOrder]struct{}
+	// Orders in the order they were received, the oldest are forgotten first
+	order []recentOrder
+}
+
+// Records an order, returns false if it was already received
+func (r *recentOrders) add(chainID int64, hash common.Hash) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := recentOrder{chainID, hash}
+	if _, ok := r.seen[key]; ok {
+		return false
+	}
+
+	if r.seen == nil {
+		r.seen = make(map[recentOrder]struct{})
+	}
+	r.seen[key] = struct{}{}
+	r.order = append(r.order, key)
+
+	if len(r.order) > recentOrdersSize {
+		delete(r.seen, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	return true
+}
+
+// Returns a gossipsub validator that rejects orders that cannot be decoded or fail validation,
+// so they are neither stored nor forwarded to other peers. Orders published by self were validated
+// when they were submitted.
+func orderValidator(self peer.ID, sl *listener.SeaportListener, vs order.Validators) pubsub.ValidatorEx {
+	return func(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		if pid == self {
+			return pubsub.ValidationAccept
+		}
+
+		c, sig, err := decodeGossipOrder(msg.Data, sl)
+		if err != nil {
+			log.Printf("Rejected order from %v: %v", pid, err.Error())
+			return pubsub.ValidationReject
+		}
+
+		o, res, err := validateOrder(ctx, vs, c, sig)
+		if err != nil {
+			log.Printf("Failed to validate order %s from %v: %v", order.Hash(c).Hex(), pid, err.Error())
+			return pubsub.ValidationIgnore
+		}
+
+		if res.Fatal() {
+			log.Printf("Rejected order %s from %v: %v", res.Hash.Hex(), pid, res.Errors)
+			return pubsub.ValidationReject
+		}
+
+		msg.ValidatorData = o
+
+		return pubsub.ValidationAccept
+	}
+}
+
+// Validates an order against the Seaport deployment it was signed for. The returned order is
+// flagged with the result, orders the offerer can still fix are kept but flagged. Orders whose
+// signature verifies for none of the deployments fail with no order returned.
+func validateOrder(ctx context.Context, vs order.Validators, c *abi.OrderComponents, sig []byte) (*db.Order, *order.ValidationResult, error) {
+	v, err := vs.ForOrder(ctx, c, sig)
+	if order.IsSignatureError(err) {
+		return nil, &order.ValidationResult{Hash: order.Hash(c), Errors: []order.ErrorCode{order.CodeInvalidSignature}}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := v.ValidateSigned(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o := db.NewOrderFromComponents(v.Domain, res.Hash, *c, sig)
+	o.Status = db.StatusFromResult(res)
+
+	return o, res, nil
+}
+
+// Validates a signed order, stores it and publishes it to the gossip topic of its collection. Orders
+// failing a check the offerer cannot fix by topping up their balance or approvals are rejected with an
+// *order.ValidationError.
+func (n *Node) SubmitOrder(ctx context.Context, chainID int64, c *abi.OrderComponents, sig []byte) (*db.Order, error) {
+	chain, ok := n.Chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain %d is not followed: %w", chainID, order.ErrNoDeployment)
+	}
+
+	o, res, err := validateOrder(ctx, chain.Validators, c, sig)
+	if err != nil {
+		log.Printf("Failed to validate submitted order %s: %v", order.Hash(c).Hex(), err.Error())
+		return nil, err
+	}
+
+	if res.Fatal() {
+		return nil, &order.ValidationError{Result: res}
+	}
+
+	if err := n.Store.PutOrder(ctx, o); err != nil {
+		log.Printf("Failed to save submitted order %s to the database: %v", o.Hash.Hex(), err.Error())
+		return nil, err
+	}
+	n.Bus.Publish(bus.NewOrderMessage(o))
+
+	j := NewOrderJSON(o)
+	data, err := EncodeOrder(&j)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders without an NFT are only gossiped on the wildcard topic
+	collection := AllCollections
+	if o.Collection != (common.Address{}) {
+		collection = o.Collection.Hex()
+	}
+
+	if err := chain.Topics.Publish(ctx, collection, data); err != nil {
+		log.Printf("Failed to publish submitted order %s: %v", o.Hash.Hex(), err.Error())
+		return nil, err
+	}
+
+	log.Printf("Published submitted order %s to %s", o.Hash.Hex(), TopicName(chainID, collection))
+
+	return o, nil
+}
+
+// Decodes a gossip payload into the order components and signature
+func decodeGossipOrder(data []byte, sl *listener.SeaportListener) (*abi.OrderComponents, []byte, error) {
+	j, err := DecodeOrder(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := j.Components()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, err := j.SignatureBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chainID, err := j.ChainIDInt()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if chainID != sl.ChainID.Int64() {
+		return nil, nil, ErrWrongChain
+	}
+
+	return c, sig, nil
+}
+
+// Publishes written Seaport events on the bus along with the collection of their order
+func (n *Node) publishEvents(store db.OrderStore, events []*db.EventRecord) {
+	// Looking up the collections is only worth it if someone listens
+	if n.Bus.Len() == 0 {
+		return
+	}
+
+	for _, e := range events {
+		n.Bus.Publish(bus.NewEventMessage(e, eventCollection(store, e)))
+	}
+}
+
+// Returns the collection of the order of an event. Orders that were never gossiped are unknown, the
+// collection of a fulfillment is then the first NFT it exchanged.
+func eventCollection(store db.OrderStore, e *db.EventRecord) common.Address {
+	if e.OrderHash == (common.Hash{}) {
+		return common.Address{}
+	}
+
+	o, err := store.GetOrder(context.Background(), e.ChainID, e.OrderHash)
+	if err == nil {
+		return o.Collection
+	}
+	if !errors.Is(err, db.ErrOrderNotFound) {
+		log.Printf("Failed to get order %s of %s: %v", e.OrderHash.Hex(), e.Type, err.Error())
+	}
+
+	for _, item := range e.Offer {
+		if abi.IsNFT(item.ItemType) {
+			return item.Token
+		}
+	}
+
+	for _, item := range e.Consideration {
+		if abi.IsNFT(item.ItemType) {
+			return item.Token
+		}
+	}
+
+	return common.Address{}
+}
@@ -0,0 +1,146 @@
+package node
+
+import (
+	"context"
+	"goport/bus"
+	"goport/db"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Returns a gossipsub router on a host listening on the loopback interface
+func newTestGossipHost(t *testing.T) (host.Host, *pubsub.PubSub) {
+	t.Helper()
+
+	h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err != nil {
+		t.Fatalf("libp2p.New: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ps, err := pubsub.NewGossipSub(ctx, h)
+	if err != nil {
+		t.Fatalf("NewGossipSub: %v", err)
+	}
+
+	return h, ps
+}
+
+// Waits until the router knows that peer is subscribed to the topic
+func waitTopicPeer(t *testing.T, ps *pubsub.PubSub, topic string, p peer.ID) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, id := range ps.ListPeers(topic) {
+			if id == p {
+				return
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("%v did not join %s", p, topic)
+}
+
+func TestHandleSubDropsCopies(t *testing.T) {
+	sender, senderPS := newTestGossipHost(t)
+	receiver, receiverPS := newTestGossipHost(t)
+
+	if err := sender.Connect(context.Background(), peer.AddrInfo{ID: receiver.ID(), Addrs: receiver.Addrs()}); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	n := &Node{Host: receiver, Store: db.NewMemoryStore(), Bus: bus.New(16)}
+	received := n.Bus.Subscribe(bus.Filter{})
+
+	// The orders are told apart by their data instead of being decoded and validated
+	validator := func(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		msg.ValidatorData = &db.Order{ChainID: 1, Hash: common.BytesToHash(msg.Data)}
+		return pubsub.ValidationAccept
+	}
+
+	wg := &sync.WaitGroup{}
+	tm := NewTopicManager(1, receiverPS, wg, n.handleSub, validator)
+	for _, c := range []string{testCollection, AllCollections} {
+		if err := tm.Join(c); err != nil {
+			t.Fatalf("Join: %v", err)
+		}
+	}
+
+	publisher := NewTopicManager(1, senderPS, &sync.WaitGroup{}, nil, nil)
+	for _, c := range []string{testCollection, AllCollections} {
+		if _, err := publisher.topic(c); err != nil {
+			t.Fatalf("topic: %v", err)
+		}
+		waitTopicPeer(t, senderPS, TopicName(1, c), receiver.ID())
+	}
+
+	for _, data := range []string{"first", "second"} {
+		if err := publisher.Publish(context.Background(), testCollection, []byte(data)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	// Each order is received on both topics but only published once
+	seen := make(map[common.Hash]int)
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case m := <-received.Messages():
+			seen[m.Order.Hash]++
+		case <-timeout:
+			done = true
+		}
+	}
+
+	for _, data := range []string{"first", "second"} {
+		if n := seen[common.BytesToHash([]byte(data))]; n != 1 {
+			t.Errorf("order %q published %d times on the bus, want once", data, n)
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("bus got %d orders, want 2", len(seen))
+	}
+
+	for _, c := range []string{testCollection, AllCollections} {
+		if err := tm.Leave(c); err != nil {
+			t.Fatalf("Leave: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestRecentOrdersForgetsOldest(t *testing.T) {
+	var r recentOrders
+
+	first := common.BytesToHash([]byte{1})
+	if !r.add(1, first) || r.add(1, first) {
+		t.Fatal("an order must only be new the first time")
+	}
+
+	// The same hash on another chain is another order
+	if !r.add(2, first) {
+		t.Fatal("order of another chain was dropped")
+	}
+
+	for i := 0; i < recentOrdersSize; i++ {
+		r.add(3, common.BigToHash(big.NewInt(int64(i))))
+	}
+
+	if !r.add(1, first) {
+		t.Fatal("oldest order was not forgotten")
+	}
+}
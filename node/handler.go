@@ -0,0 +1,131 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-msgio"
+)
+
+// Time allowed for a peer to send a request or read a response
+const streamTimeout = 30 * time.Second
+
+// Source of the orders served to other nodes over the wire protocol
+type OrderSource interface {
+	GetOrders(ctx context.Context, collection string, opts GetOrdersOpts) ([]OrderJSON, error)
+	GetOrderHashes(ctx context.Context, collection string, opts GetOrdersOpts) ([]string, error)
+	GetOrderCount(ctx context.Context, collection string, opts GetOrdersOpts) (uint32, error)
+	GetCriteria(ctx context.Context, hash string) ([]string, error)
+}
+
+// Registers the wire protocol stream handler on the host
+func (n *Node) setStreamHandlers() {
+	n.Host.SetStreamHandler(ProtocolID, n.handleStream)
+}
+
+// Reads a single request from the stream and writes back the matching response
+func (n *Node) handleStream(s network.Stream) {
+	defer s.Close()
+
+	if err := s.SetDeadline(time.Now().Add(streamTimeout)); err != nil {
+		log.Printf("Failed to set stream deadline: %v", err.Error())
+		s.Reset()
+		return
+	}
+
+	r := msgio.NewVarintReaderSize(s, maxMessageSize)
+	w := msgio.NewVarintWriter(s)
+
+	req, err := readRequest(r)
+	if err != nil {
+		log.Printf("Failed to read request from %v: %v", s.Conn().RemotePeer(), err.Error())
+		s.Reset()
+		return
+	}
+
+	res, err := n.respond(context.Background(), req)
+	if err != nil {
+		log.Printf("Failed to handle request from %v: %v", s.Conn().RemotePeer(), err.Error())
+		s.Reset()
+		return
+	}
+
+	if err := writeMessage(w, res); err != nil {
+		log.Printf("Failed to write response to %v: %v", s.Conn().RemotePeer(), err.Error())
+		s.Reset()
+	}
+}
+
+// Builds the response to a wire request
+func (n *Node) respond(ctx context.Context, req message) (message, error) {
+	switch req := req.(type) {
+	case *GetOrders:
+		res := &Orders{ReqID: req.ReqID, Orders: []OrderJSON{}}
+		if n.Orders == nil {
+			return res, nil
+		}
+
+		orders, err := n.Orders.GetOrders(ctx, req.Collection, clampOpts(req.Opts))
+		if err != nil {
+			return nil, err
+		}
+		res.Orders = orders
+
+		return res, nil
+
+	case *GetOrderHashes:
+		res := &OrderHashes{ReqID: req.ReqID, Hashes: []string{}}
+		if n.Orders == nil {
+			return res, nil
+		}
+
+		hashes, err := n.Orders.GetOrderHashes(ctx, req.Collection, clampOpts(req.Opts))
+		if err != nil {
+			return nil, err
+		}
+		res.Hashes = hashes
+
+		return res, nil
+
+	case *GetOrderCount:
+		res := &OrderCount{ReqID: req.ReqID}
+		if n.Orders == nil {
+			return res, nil
+		}
+
+		count, err := n.Orders.GetOrderCount(ctx, req.Collection, req.Opts)
+		if err != nil {
+			return nil, err
+		}
+		res.Count = count
+
+		return res, nil
+
+	case *GetCriteria:
+		res := &Criteria{ReqID: req.ReqID, Hash: req.Hash, TokenIDs: []string{}}
+		if n.Orders == nil {
+			return res, nil
+		}
+
+		ids, err := n.Orders.GetCriteria(ctx, req.Hash)
+		if err != nil {
+			return nil, err
+		}
+		res.TokenIDs = ids
+
+		return res, nil
+	}
+
+	return nil, ErrUnexpectedMessage
+}
+
+// Caps the number of results a peer can request at once
+func clampOpts(opts GetOrdersOpts) GetOrdersOpts {
+	if opts.Count == 0 || opts.Count > MaxOrdersPerResponse {
+		opts.Count = MaxOrdersPerResponse
+	}
+
+	return opts
+}
@@ -2,11 +2,12 @@ package node
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
+	"goport/api"
+	"goport/bus"
 	"goport/config"
 	"goport/db"
 	"goport/listener"
+	"goport/order"
 	"log"
 	"sync"
 
@@ -15,14 +16,39 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	cfg "github.com/libp2p/go-libp2p/config"
 	"github.com/libp2p/go-libp2p/core/host"
-	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
-	"github.com/uptrace/bun/driver/sqliteshim"
 )
 
 type Node struct {
 	Host host.Host
-	db.SQLWrapper
+
+	// Storage of orders and events, the SQL database from the config if nil when the node starts
+	Store db.Store
+
+	// Orders served to other nodes over the wire protocol
+	Orders OrderSource
+
+	// New orders and written Seaport events, created when the node starts if nil
+	Bus *bus.Bus
+
+	// Followed chains by chain id
+	Chains map[int64]*Chain
+
+	// Orders received from the gossip topics, the same order arrives on its collection topic and on
+	// the wildcard topic
+	received recentOrders
+
+	reqID uint32
+}
+
+// Listener, validators and gossip topics of a single chain
+type Chain struct {
+	Listener *listener.SeaportListener
+
+	// Per-collection order gossip topics
+	Topics *TopicManager
+
+	// Validate the orders received from the network, one per Seaport deployment
+	Validators order.Validators
 }
 
 type NodeConfig struct{}
@@ -42,26 +68,35 @@ func New(option ...cfg.Option) (*Node, error) {
 
 // Start the node
 func (n *Node) Start(wg *sync.WaitGroup) error {
-	// Open MySQL connection
-	s, err := sql.Open(sqliteshim.ShimName, fmt.Sprintf("file:%s:", config.DB_NAME))
-	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err.Error())
-		return err
-	}
+	if n.Store == nil {
+		sqlStore, err := db.Open(config.DB_DIALECT, config.DB_NAME)
+		if err != nil {
+			log.Fatalf("Failed to connect to the database: %v", err.Error())
+			return err
+		}
 
-	db := &db.SQLWrapper{
-		DB: bun.NewDB(s, sqlitedialect.New()),
+		// Create or upgrade the schema before anything writes to it
+		if err := sqlStore.Migrate(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate the database: %v", err.Error())
+			return err
+		}
+
+		n.Store = sqlStore
 	}
 
-	// Create a new seaport contract listiner
-	sl, err := listener.New()
-	if err != nil {
-		log.Fatalf("Failed to create new SeaportListener: %v", err.Error())
-		return err
+	if n.Bus == nil {
+		n.Bus = bus.New(bus.DefaultBuffer)
 	}
 
-	// Start the seaport listener
-	sl.Start(wg, db)
+	// Events and checkpoints are written in batches by a single goroutine, which publishes them once
+	// they are committed
+	store := n.Store
+	writer := db.NewWriter(store, int(config.WRITE_BATCH_SIZE), config.WRITE_FLUSH_INTERVAL)
+	writer.OnWrite = func(events []*db.EventRecord) {
+		n.publishEvents(store, events)
+	}
+	writer.Start(wg)
+	n.Store = writer
 
 	// Create a new DHT
 	dht := kad.NewDHT(context.Background(), n.Host, nil)
@@ -73,19 +108,37 @@ func (n *Node) Start(wg *sync.WaitGroup) error {
 		return err
 	}
 
-	mt, _ := ps.Join("gossipsub:message")
-	sub, err := mt.Subscribe(func(subscription *pubsub.Subscription) error {
-		log.Printf("Subscription Data: %v", subscription)
+	n.Chains = make(map[int64]*Chain)
+	first := int64(0)
+	for _, c := range config.CHAINS {
+		chainID, err := n.startChain(wg, ps, c)
+		if err != nil {
+			return err
+		}
 
-		return nil
-	})
+		if first == 0 {
+			first = chainID
+		}
+	}
 
-	if err != nil {
-		log.Fatalf("Failed to subscribe to gossipsub: %v", err.Error())
-		return err
+	// Serve the wire protocol to other nodes, requests without a chain id get the first chain
+	if n.Orders == nil {
+		n.Orders = &dbOrderSource{db: n.Store, chainID: first}
 	}
+	n.setStreamHandlers()
+
+	// Serve the HTTP API, order lookups and submissions without a chain id get the first chain
+	if config.API_PORT != "" {
+		s, err := api.NewServer(n.Store, first)
+		if err != nil {
+			log.Fatalf("Failed to create the API server: %v", err.Error())
+			return err
+		}
+		s.Submitter = n
+		s.Bus = n.Bus
 
-	handleSub(wg, sub, db)
+		s.Start(wg, ":"+config.API_PORT)
+	}
 
 	err = dht.Bootstrap(context.Background())
 	if err != nil {
@@ -96,33 +149,61 @@ func (n *Node) Start(wg *sync.WaitGroup) error {
 	return nil
 }
 
-// TODO: Handle errors
-func handleSub(wg *sync.WaitGroup, sub *pubsub.Subscription, database *db.SQLWrapper) {
-	wg.Add(1)
+// Starts the listener of a chain and joins its gossip topics, returns the chain id reported by its RPC endpoint
+func (n *Node) startChain(wg *sync.WaitGroup, ps *pubsub.PubSub, c config.Chain) (int64, error) {
+	// Create a new seaport contract listiner
+	sl, err := listener.New(c)
+	if err != nil {
+		log.Fatalf("Failed to create new SeaportListener for %s: %v", c.Name, err.Error())
+		return 0, err
+	}
 
-	go func() {
-		for {
-			msg, err := sub.Next(context.Background())
-			if err != nil {
-				log.Printf("Failed to get next message: %v", err.Error())
-				sub.Cancel()
-				break
-			}
+	chain := &Chain{Listener: sl}
 
-			log.Printf("Message: %v", msg)
+	for _, d := range sl.Deployments {
+		v, err := order.NewValidator(sl.Client, d.Domain())
+		if err != nil {
+			log.Printf("Failed to create order validator for Seaport %s on %s: %v", d.Version, c.Name, err.Error())
+			return 0, err
+		}
 
-			// Save message to the database
-			database.Lock()
-			defer database.Unlock()
+		chain.Validators = append(chain.Validators, v)
+	}
 
-			_, err = database.DB.NewInsert().Model(msg).Exec(context.Background())
-			if err != nil {
-				log.Printf("Failed to save message to the database: %v", err.Error())
-				break
-			}
+	// Start the seaport listener, rolled back events re-validate their orders
+	sl.Validators = chain.Validators
 
+	// Catch up on the events emitted before the listener started, the backfill covers the confirmed
+	// blocks and the listener continues after the last of them
+	if c.Backfill {
+		to, err := sl.ConfirmedHead(context.Background())
+		if err != nil {
+			log.Printf("Failed to get head block for backfill on %s: %v", c.Name, err.Error())
+			return 0, err
 		}
 
-		wg.Done()
-	}()
+		sl.ResumeAfter(to)
+
+		for _, d := range sl.Deployments {
+			sl.NewBackfiller(n.Store, d, c.BackfillFromBlock, config.BACKFILL_CHUNK_SIZE).Start(wg, to)
+		}
+	}
+
+	sl.Start(wg, n.Store)
+
+	// Re-validate stored orders when their offered tokens move
+	sl.WatchTokens(wg, n.Store, chain.Validators)
+
+	chain.Topics = NewTopicManager(sl.ChainID.Int64(), ps, wg, n.handleSub, orderValidator(n.Host.ID(), sl, chain.Validators))
+
+	for _, col := range config.COLLECTIONS {
+		if err := chain.Topics.Join(col); err != nil {
+			log.Printf("Failed to join topic for collection %s on %s: %v", col, c.Name, err.Error())
+			return 0, err
+		}
+	}
+
+	n.Chains[sl.ChainID.Int64()] = chain
+
+	return sl.ChainID.Int64(), nil
 }
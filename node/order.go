@@ -0,0 +1,229 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"goport/abi"
+	"goport/db"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var (
+	ErrInvalidNumber  = errors.New("invalid number in order")
+	ErrInvalidAddress = errors.New("invalid address in order")
+	ErrInvalidBytes32 = errors.New("invalid bytes32 in order")
+	ErrEmptyOrder     = errors.New("order has no offer or consideration items")
+)
+
+// Decodes a gossip message payload into an order
+func DecodeOrder(data []byte) (*OrderJSON, error) {
+	o := new(OrderJSON)
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Encodes an order into a gossip message payload
+func EncodeOrder(o *OrderJSON) ([]byte, error) {
+	return json.Marshal(o)
+}
+
+// Creates the wire representation of a stored order
+func NewOrderJSON(o *db.Order) OrderJSON {
+	c := o.Components()
+
+	j := OrderJSON{
+		Offer:         make([]OrderItemJSON, len(c.Offer)),
+		Consideration: make([]OrderItemJSON, len(c.Consideration)),
+		Offerer:       c.Offerer.Hex(),
+		Signature:     hexutil.Encode(o.Signature),
+		OrderType:     c.OrderType,
+		StartTime:     c.StartTime.String(),
+		EndTime:       c.EndTime.String(),
+		Counter:       c.Counter.String(),
+		Salt:          c.Salt.String(),
+		ConduitKey:    hexutil.Encode(c.ConduitKey[:]),
+		Zone:          c.Zone.Hex(),
+		ZoneHash:      hexutil.Encode(c.ZoneHash[:]),
+		ChainID:       big.NewInt(o.ChainID).String(),
+	}
+
+	for i, item := range c.Offer {
+		j.Offer[i] = OrderItemJSON{
+			ItemType:             item.ItemType,
+			Token:                item.Token.Hex(),
+			IdentifierOrCriteria: item.IdentifierOrCriteria.String(),
+			StartAmount:          item.StartAmount.String(),
+			EndAmount:            item.EndAmount.String(),
+		}
+	}
+
+	for i, item := range c.Consideration {
+		j.Consideration[i] = OrderItemJSON{
+			ItemType:             item.ItemType,
+			Token:                item.Token.Hex(),
+			IdentifierOrCriteria: item.IdentifierOrCriteria.String(),
+			StartAmount:          item.StartAmount.String(),
+			EndAmount:            item.EndAmount.String(),
+			Recipient:            item.Recipient.Hex(),
+		}
+	}
+
+	return j
+}
+
+// Returns the order components signed by the offerer
+func (o *OrderJSON) Components() (*abi.OrderComponents, error) {
+	if len(o.Offer) == 0 && len(o.Consideration) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	c := &abi.OrderComponents{
+		Offer:         make([]abi.OfferItem, len(o.Offer)),
+		Consideration: make([]abi.ConsiderationItem, len(o.Consideration)),
+		OrderType:     o.OrderType,
+	}
+
+	var err error
+	if c.Offerer, err = parseAddress(o.Offerer); err != nil {
+		return nil, err
+	}
+	if c.Zone, err = parseAddress(o.Zone); err != nil {
+		return nil, err
+	}
+	if c.StartTime, err = parseBig(o.StartTime); err != nil {
+		return nil, err
+	}
+	if c.EndTime, err = parseBig(o.EndTime); err != nil {
+		return nil, err
+	}
+	if c.ZoneHash, err = parseBytes32(o.ZoneHash); err != nil {
+		return nil, err
+	}
+	if c.Salt, err = parseBig(o.Salt); err != nil {
+		return nil, err
+	}
+	if c.ConduitKey, err = parseBytes32(o.ConduitKey); err != nil {
+		return nil, err
+	}
+	if c.Counter, err = parseBig(o.Counter); err != nil {
+		return nil, err
+	}
+
+	for i, item := range o.Offer {
+		token, id, start, end, err := parseItem(item)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Offer[i] = abi.OfferItem{
+			ItemType:             item.ItemType,
+			Token:                token,
+			IdentifierOrCriteria: id,
+			StartAmount:          start,
+			EndAmount:            end,
+		}
+	}
+
+	for i, item := range o.Consideration {
+		token, id, start, end, err := parseItem(item)
+		if err != nil {
+			return nil, err
+		}
+
+		recipient, err := parseAddress(item.Recipient)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Consideration[i] = abi.ConsiderationItem{
+			ItemType:             item.ItemType,
+			Token:                token,
+			IdentifierOrCriteria: id,
+			StartAmount:          start,
+			EndAmount:            end,
+			Recipient:            recipient,
+		}
+	}
+
+	return c, nil
+}
+
+// Returns the decoded order signature
+func (o *OrderJSON) SignatureBytes() ([]byte, error) {
+	return hexutil.Decode(o.Signature)
+}
+
+// Returns the chain id the order was signed for
+func (o *OrderJSON) ChainIDInt() (int64, error) {
+	id, err := parseBig(o.ChainID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !id.IsInt64() {
+		return 0, ErrInvalidNumber
+	}
+
+	return id.Int64(), nil
+}
+
+func parseItem(item OrderItemJSON) (common.Address, *big.Int, *big.Int, *big.Int, error) {
+	token, err := parseAddress(item.Token)
+	if err != nil {
+		return common.Address{}, nil, nil, nil, err
+	}
+
+	id, err := parseBig(item.IdentifierOrCriteria)
+	if err != nil {
+		return common.Address{}, nil, nil, nil, err
+	}
+
+	start, err := parseBig(item.StartAmount)
+	if err != nil {
+		return common.Address{}, nil, nil, nil, err
+	}
+
+	end, err := parseBig(item.EndAmount)
+	if err != nil {
+		return common.Address{}, nil, nil, nil, err
+	}
+
+	return token, id, start, end, nil
+}
+
+// Parses a decimal or 0x prefixed hex uint256
+func parseBig(s string) (*big.Int, error) {
+	n, ok := math.ParseBig256(s)
+	if !ok || n.Sign() < 0 {
+		return nil, ErrInvalidNumber
+	}
+
+	return n, nil
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, ErrInvalidAddress
+	}
+
+	return common.HexToAddress(s), nil
+}
+
+func parseBytes32(s string) ([32]byte, error) {
+	var b [32]byte
+
+	d, err := hexutil.Decode(s)
+	if err != nil || len(d) > 32 {
+		return b, ErrInvalidBytes32
+	}
+	copy(b[32-len(d):], d)
+
+	return b, nil
+}
@@ -0,0 +1,94 @@
+package node
+
+import (
+	"bytes"
+	"errors"
+	"goport/abi"
+	"goport/db"
+	"goport/order"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Returns a listing of a token of testCollection on chain 137
+func testGossipOrder() *db.Order {
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	c := abi.OrderComponents{
+		Offerer: offerer,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC721, Token: common.HexToAddress(testCollection), IdentifierOrCriteria: big.NewInt(7), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(1e18), EndAmount: big.NewInt(5e17), Recipient: offerer},
+		},
+		OrderType:  abi.OrderTypeFullOpen,
+		StartTime:  big.NewInt(1),
+		EndTime:    big.NewInt(1 << 40),
+		ZoneHash:   common.HexToHash("0x01"),
+		Salt:       new(big.Int).Lsh(big.NewInt(1), 200),
+		ConduitKey: common.HexToHash("0x02"),
+		Counter:    big.NewInt(4),
+	}
+
+	d := order.NewDomain(big.NewInt(137), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+
+	return db.NewOrderFromComponents(d, order.Hash(&c), c, bytes.Repeat([]byte{7}, 65))
+}
+
+func TestGossipOrderRoundTrip(t *testing.T) {
+	o := testGossipOrder()
+
+	j := NewOrderJSON(o)
+	data, err := EncodeOrder(&j)
+	if err != nil {
+		t.Fatalf("EncodeOrder: %v", err)
+	}
+
+	decoded, err := DecodeOrder(data)
+	if err != nil {
+		t.Fatalf("DecodeOrder: %v", err)
+	}
+
+	c, err := decoded.Components()
+	if err != nil {
+		t.Fatalf("Components: %v", err)
+	}
+	if h := order.Hash(c); h != o.Hash {
+		t.Fatalf("decoded order hashes to %s, want %s", h.Hex(), o.Hash.Hex())
+	}
+
+	sig, err := decoded.SignatureBytes()
+	if err != nil || !bytes.Equal(sig, o.Signature) {
+		t.Fatalf("decoded signature %x, %v, want %x", sig, err, o.Signature)
+	}
+
+	if id, err := decoded.ChainIDInt(); err != nil || id != 137 {
+		t.Fatalf("decoded chain id %d, %v, want 137", id, err)
+	}
+}
+
+func TestGossipOrderInvalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(j *OrderJSON)
+		err    error
+	}{
+		{"no items", func(j *OrderJSON) { j.Offer, j.Consideration = nil, nil }, ErrEmptyOrder},
+		{"bad offerer", func(j *OrderJSON) { j.Offerer = "0x1234" }, ErrInvalidAddress},
+		{"negative amount", func(j *OrderJSON) { j.Offer[0].StartAmount = "-1" }, ErrInvalidNumber},
+		{"amount past uint256", func(j *OrderJSON) { j.Salt = "0x1" + string(bytes.Repeat([]byte{'0'}, 64)) }, ErrInvalidNumber},
+		{"long zone hash", func(j *OrderJSON) { j.ZoneHash = "0x" + string(bytes.Repeat([]byte{'1'}, 66)) }, ErrInvalidBytes32},
+		{"bad recipient", func(j *OrderJSON) { j.Consideration[0].Recipient = "" }, ErrInvalidAddress},
+	}
+
+	for _, tc := range cases {
+		j := NewOrderJSON(testGossipOrder())
+		tc.modify(&j)
+
+		if _, err := j.Components(); !errors.Is(err, tc.err) {
+			t.Errorf("%s: Components = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"goport/db"
+
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/libp2p/go-msgio"
+)
+
+// Protocol id of the seaport-gossip request/response wire protocol.
+//
+// The protocol id, the message codes and the message fields follow seaport-gossip, but the message
+// bodies are JSON, framed with a varint length and the message code byte, see writeMessage. This
+// encoding has not been checked against frames of the TypeScript nodes and is not known to match
+// theirs, so the wire protocol is only known to work between goport nodes. The frames goport sends
+// are pinned in testdata/wire.
+const ProtocolID = protocol.ID("/seaport-gossip/0.0.1/wire")
+
+// Maximum size of a single wire message, large enough for a full page of orders
+const maxMessageSize = 4 << 20
+
+// Maximum number of orders or hashes sent in a single response
+const MaxOrdersPerResponse = 100
+
+// Message codes prefixed to every wire message
+type MessageCode byte
+
+const (
+	GetOrdersCode      MessageCode = 0x01
+	OrdersCode         MessageCode = 0x02
+	GetCriteriaCode    MessageCode = 0x03
+	CriteriaCode       MessageCode = 0x04
+	GetOrderCountCode  MessageCode = 0x05
+	OrderCountCode     MessageCode = 0x06
+	GetOrderHashesCode MessageCode = 0x07
+	OrderHashesCode    MessageCode = 0x08
+)
+
+var (
+	ErrEmptyMessage      = errors.New("empty wire message")
+	ErrUnknownMessage    = errors.New("unknown wire message code")
+	ErrUnexpectedMessage = errors.New("unexpected wire message code")
+	ErrRequestIDMismatch = errors.New("response request id does not match request")
+)
+
+// Side of the order book to query
+type OrderSide = db.OrderSide
+
+const (
+	SellSide = db.SellSide
+	BuySide  = db.BuySide
+)
+
+// Sort orders for GetOrders and GetOrderHashes
+type OrderSort = db.OrderSort
+
+const (
+	SortNewest            = db.SortNewest
+	SortOldest            = db.SortOldest
+	SortEndingSoon        = db.SortEndingSoon
+	SortPriceLowToHigh    = db.SortPriceLowToHigh
+	SortPriceHighToLow    = db.SortPriceHighToLow
+	SortRecentlyFulfilled = db.SortRecentlyFulfilled
+	SortRecentlyValidated = db.SortRecentlyValidated
+)
+
+// Options shared by GetOrders, GetOrderCount and GetOrderHashes
+type GetOrdersOpts struct {
+	Side   OrderSide `json:"side"`
+	Count  uint32    `json:"count"`
+	Offset uint32    `json:"offset"`
+	Sort   OrderSort `json:"sort"`
+
+	// Chain of the orders, the node's first chain when unset
+	ChainID int64 `json:"chainId,omitempty"`
+}
+
+// Order item as sent over the wire, amounts are decimal strings
+type OrderItemJSON struct {
+	ItemType             uint8  `json:"itemType"`
+	Token                string `json:"token"`
+	IdentifierOrCriteria string `json:"identifierOrCriteria"`
+	StartAmount          string `json:"startAmount"`
+	EndAmount            string `json:"endAmount"`
+	Recipient            string `json:"recipient,omitempty"`
+}
+
+// Signed order as sent over the wire and in gossip messages
+type OrderJSON struct {
+	Offer         []OrderItemJSON `json:"offer"`
+	Consideration []OrderItemJSON `json:"consideration"`
+	Offerer       string          `json:"offerer"`
+	Signature     string          `json:"signature"`
+	OrderType     uint8           `json:"orderType"`
+	StartTime     string          `json:"startTime"`
+	EndTime       string          `json:"endTime"`
+	Counter       string          `json:"counter"`
+	Salt          string          `json:"salt"`
+	ConduitKey    string          `json:"conduitKey"`
+	Zone          string          `json:"zone"`
+	ZoneHash      string          `json:"zoneHash"`
+	ChainID       string          `json:"chainId"`
+}
+
+type GetOrders struct {
+	ReqID      uint32        `json:"reqId"`
+	Collection string        `json:"collection"`
+	Opts       GetOrdersOpts `json:"opts"`
+}
+
+type Orders struct {
+	ReqID  uint32      `json:"reqId"`
+	Orders []OrderJSON `json:"orders"`
+}
+
+type GetCriteria struct {
+	ReqID uint32 `json:"reqId"`
+	Hash  string `json:"hash"`
+}
+
+type Criteria struct {
+	ReqID    uint32   `json:"reqId"`
+	Hash     string   `json:"hash"`
+	TokenIDs []string `json:"tokenIds"`
+}
+
+type GetOrderCount struct {
+	ReqID      uint32        `json:"reqId"`
+	Collection string        `json:"collection"`
+	Opts       GetOrdersOpts `json:"opts"`
+}
+
+type OrderCount struct {
+	ReqID uint32 `json:"reqId"`
+	Count uint32 `json:"count"`
+}
+
+type GetOrderHashes struct {
+	ReqID      uint32        `json:"reqId"`
+	Collection string        `json:"collection"`
+	Opts       GetOrdersOpts `json:"opts"`
+}
+
+type OrderHashes struct {
+	ReqID  uint32   `json:"reqId"`
+	Hashes []string `json:"hashes"`
+}
+
+// Message sent over the wire protocol
+type message interface {
+	code() MessageCode
+	requestID() uint32
+}
+
+func (m *GetOrders) code() MessageCode      { return GetOrdersCode }
+func (m *Orders) code() MessageCode         { return OrdersCode }
+func (m *GetCriteria) code() MessageCode    { return GetCriteriaCode }
+func (m *Criteria) code() MessageCode       { return CriteriaCode }
+func (m *GetOrderCount) code() MessageCode  { return GetOrderCountCode }
+func (m *OrderCount) code() MessageCode     { return OrderCountCode }
+func (m *GetOrderHashes) code() MessageCode { return GetOrderHashesCode }
+func (m *OrderHashes) code() MessageCode    { return OrderHashesCode }
+
+func (m *GetOrders) requestID() uint32      { return m.ReqID }
+func (m *Orders) requestID() uint32         { return m.ReqID }
+func (m *GetCriteria) requestID() uint32    { return m.ReqID }
+func (m *Criteria) requestID() uint32       { return m.ReqID }
+func (m *GetOrderCount) requestID() uint32  { return m.ReqID }
+func (m *OrderCount) requestID() uint32     { return m.ReqID }
+func (m *GetOrderHashes) requestID() uint32 { return m.ReqID }
+func (m *OrderHashes) requestID() uint32    { return m.ReqID }
+
+// Returns an empty request message for the given code
+func newRequest(code MessageCode) (message, error) {
+	switch code {
+	case GetOrdersCode:
+		return &GetOrders{}, nil
+	case GetCriteriaCode:
+		return &GetCriteria{}, nil
+	case GetOrderCountCode:
+		return &GetOrderCount{}, nil
+	case GetOrderHashesCode:
+		return &GetOrderHashes{}, nil
+	case OrdersCode, CriteriaCode, OrderCountCode, OrderHashesCode:
+		return nil, ErrUnexpectedMessage
+	}
+
+	return nil, ErrUnknownMessage
+}
+
+// Writes a length prefixed message, the first byte of the payload is the message code
+func writeMessage(w msgio.Writer, msg message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteMsg(append([]byte{byte(msg.code())}, b...))
+}
+
+// Reads a length prefixed request and decodes it according to its message code
+func readRequest(r msgio.Reader) (message, error) {
+	b, err := r.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	defer r.ReleaseMsg(b)
+
+	if len(b) == 0 {
+		return nil, ErrEmptyMessage
+	}
+
+	msg, err := newRequest(MessageCode(b[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b[1:], msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// Reads a length prefixed response to req and decodes it into res
+func readResponse(r msgio.Reader, req message, res message) error {
+	b, err := r.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer r.ReleaseMsg(b)
+
+	if len(b) == 0 {
+		return ErrEmptyMessage
+	}
+
+	if MessageCode(b[0]) != res.code() {
+		return ErrUnexpectedMessage
+	}
+
+	if err := json.Unmarshal(b[1:], res); err != nil {
+		return err
+	}
+
+	if res.requestID() != req.requestID() {
+		return ErrRequestIDMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package node
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/libp2p/go-msgio"
+)
+
+// Writes a message and reads it back as a request or as the response to req
+func roundTrip(t *testing.T, msg message, req message, res message) message {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	if err := writeMessage(msgio.NewVarintWriter(buf), msg); err != nil {
+		t.Fatalf("writeMessage: %v", err)
+	}
+
+	r := msgio.NewVarintReaderSize(buf, maxMessageSize)
+
+	if req == nil {
+		got, err := readRequest(r)
+		if err != nil {
+			t.Fatalf("readRequest: %v", err)
+		}
+		return got
+	}
+
+	if err := readResponse(r, req, res); err != nil {
+		t.Fatalf("readResponse: %v", err)
+	}
+	return res
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	opts := GetOrdersOpts{Side: BuySide, Count: 20, Offset: 40, Sort: SortPriceLowToHigh, ChainID: 137}
+
+	reqs := []message{
+		&GetOrders{ReqID: 1, Collection: "0x8a90cab2b38dba80c64b7734e58ee1db38b8992e", Opts: opts},
+		&GetOrderHashes{ReqID: 2, Collection: AllCollections, Opts: opts},
+		&GetOrderCount{ReqID: 3, Collection: AllCollections},
+		&GetCriteria{ReqID: 4, Hash: "0x" + string(bytes.Repeat([]byte("ab"), 32))},
+	}
+
+	for _, req := range reqs {
+		if got := roundTrip(t, req, nil, nil); !reflect.DeepEqual(got, req) {
+			t.Fatalf("request %#v decoded as %#v", req, got)
+		}
+	}
+}
+
+func TestResponseRoundTrip(t *testing.T) {
+	order := OrderJSON{
+		Offer:         []OrderItemJSON{{ItemType: 2, Token: "0x01", IdentifierOrCriteria: "1", StartAmount: "1", EndAmount: "1"}},
+		Consideration: []OrderItemJSON{{ItemType: 0, Token: "0x00", IdentifierOrCriteria: "0", StartAmount: "5", EndAmount: "5", Recipient: "0x02"}},
+		Offerer:       "0x03",
+		Signature:     "0x04",
+		StartTime:     "1",
+		EndTime:       "2",
+		Counter:       "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		Salt:          "3",
+		ConduitKey:    "0x05",
+		Zone:          "0x06",
+		ZoneHash:      "0x07",
+		ChainID:       "1",
+	}
+
+	cases := []struct {
+		req message
+		res message
+		out message
+	}{
+		{&GetOrders{ReqID: 1}, &Orders{ReqID: 1, Orders: []OrderJSON{order}}, &Orders{}},
+		{&GetOrderHashes{ReqID: 2}, &OrderHashes{ReqID: 2, Hashes: []string{"0x01", "0x02"}}, &OrderHashes{}},
+		{&GetOrderCount{ReqID: 3}, &OrderCount{ReqID: 3, Count: 4294967295}, &OrderCount{}},
+		{&GetCriteria{ReqID: 4, Hash: "0x01"}, &Criteria{ReqID: 4, Hash: "0x01", TokenIDs: []string{"1", "2"}}, &Criteria{}},
+	}
+
+	for _, c := range cases {
+		if got := roundTrip(t, c.res, c.req, c.out); !reflect.DeepEqual(got, c.res) {
+			t.Fatalf("response %#v decoded as %#v", c.res, got)
+		}
+	}
+}
+
+func TestReadResponseErrors(t *testing.T) {
+	write := func(msg message) *bytes.Buffer {
+		buf := new(bytes.Buffer)
+		if err := writeMessage(msgio.NewVarintWriter(buf), msg); err != nil {
+			t.Fatalf("writeMessage: %v", err)
+		}
+		return buf
+	}
+
+	buf := write(&OrderCount{ReqID: 2})
+	err := readResponse(msgio.NewVarintReaderSize(buf, maxMessageSize), &GetOrderCount{ReqID: 1}, &OrderCount{})
+	if !errors.Is(err, ErrRequestIDMismatch) {
+		t.Fatalf("mismatched request id: got %v, want %v", err, ErrRequestIDMismatch)
+	}
+
+	buf = write(&OrderHashes{ReqID: 1})
+	err = readResponse(msgio.NewVarintReaderSize(buf, maxMessageSize), &GetOrderCount{ReqID: 1}, &OrderCount{})
+	if !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("wrong response code: got %v, want %v", err, ErrUnexpectedMessage)
+	}
+
+	buf = write(&OrderCount{ReqID: 1})
+	if _, err := readRequest(msgio.NewVarintReaderSize(buf, maxMessageSize)); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("response read as request: got %v, want %v", err, ErrUnexpectedMessage)
+	}
+
+	buf = new(bytes.Buffer)
+	if err := msgio.NewVarintWriter(buf).WriteMsg([]byte{0x7f}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRequest(msgio.NewVarintReaderSize(buf, maxMessageSize)); !errors.Is(err, ErrUnknownMessage) {
+		t.Fatalf("unknown code: got %v, want %v", err, ErrUnknownMessage)
+	}
+}
+
+// Messages whose frames are pinned in testdata/wire, by file name
+var wireGolden = map[string]message{
+	"get_orders.hex": &GetOrders{ReqID: 1, Collection: "0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e", Opts: GetOrdersOpts{Side: BuySide, Count: 10, Offset: 20, Sort: SortPriceLowToHigh}},
+	"orders.hex": &Orders{ReqID: 1, Orders: []OrderJSON{{
+		Offer:         []OrderItemJSON{{ItemType: 2, Token: "0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e", IdentifierOrCriteria: "1", StartAmount: "1", EndAmount: "1"}},
+		Consideration: []OrderItemJSON{{ItemType: 0, Token: "0x0000000000000000000000000000000000000000", IdentifierOrCriteria: "0", StartAmount: "1000000000000000000", EndAmount: "1000000000000000000", Recipient: "0x0000000000000000000000000000000000000b0b"}},
+		Offerer:       "0x0000000000000000000000000000000000000b0b",
+		Signature:     "0x",
+		OrderType:     0,
+		StartTime:     "0",
+		EndTime:       "1700000000",
+		Counter:       "0",
+		Salt:          "1",
+		ConduitKey:    "0x0000000000000000000000000000000000000000000000000000000000000000",
+		Zone:          "0x0000000000000000000000000000000000000000",
+		ZoneHash:      "0x0000000000000000000000000000000000000000000000000000000000000000",
+		ChainID:       "1",
+	}}},
+	"get_criteria.hex":     &GetCriteria{ReqID: 2, Hash: "0x0000000000000000000000000000000000000000000000000000000000000001"},
+	"criteria.hex":         &Criteria{ReqID: 2, Hash: "0x0000000000000000000000000000000000000000000000000000000000000001", TokenIDs: []string{"1", "2"}},
+	"get_order_count.hex":  &GetOrderCount{ReqID: 3, Collection: "0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e"},
+	"order_count.hex":      &OrderCount{ReqID: 3, Count: 42},
+	"get_order_hashes.hex": &GetOrderHashes{ReqID: 4, Collection: "0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e", Opts: GetOrdersOpts{Count: 1}},
+	"order_hashes.hex":     &OrderHashes{ReqID: 4, Hashes: []string{"0x0000000000000000000000000000000000000000000000000000000000000002"}},
+}
+
+// The frames goport writes must not change without updating testdata/wire, other nodes read them
+func TestWireGolden(t *testing.T) {
+	for name, msg := range wireGolden {
+		b, err := os.ReadFile(filepath.Join("testdata", "wire", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := writeMessage(msgio.NewVarintWriter(buf), msg); err != nil {
+			t.Fatalf("writeMessage: %v", err)
+		}
+
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("frame of %s = %x, want %x", name, buf.Bytes(), want)
+		}
+
+		if _, err := newRequest(msg.code()); err != nil {
+			continue
+		}
+
+		got, err := readRequest(msgio.NewVarintReaderSize(bytes.NewReader(want), maxMessageSize))
+		if err != nil {
+			t.Fatalf("readRequest of %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, msg) {
+			t.Fatalf("readRequest of %s = %+v, want %+v", name, got, msg)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"goport/db"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Serves the orders in the store over the wire protocol
+type dbOrderSource struct {
+	db db.OrderStore
+
+	// Chain served to requests that do not set one
+	chainID int64
+}
+
+func (s *dbOrderSource) GetOrders(ctx context.Context, collection string, opts GetOrdersOpts) ([]OrderJSON, error) {
+	q, err := s.orderQuery(collection, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.db.QueryOrders(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]OrderJSON, len(orders))
+	for i, o := range orders {
+		res[i] = NewOrderJSON(o)
+	}
+
+	return res, nil
+}
+
+func (s *dbOrderSource) GetOrderHashes(ctx context.Context, collection string, opts GetOrdersOpts) ([]string, error) {
+	q, err := s.orderQuery(collection, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := s.db.QueryOrderHashes(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(hashes))
+	for i, h := range hashes {
+		res[i] = h.Hex()
+	}
+
+	return res, nil
+}
+
+func (s *dbOrderSource) GetOrderCount(ctx context.Context, collection string, opts GetOrdersOpts) (uint32, error) {
+	q, err := s.orderQuery(collection, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.db.CountOrders(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	// The count is a uint32 on the wire
+	if int64(count) > math.MaxUint32 {
+		count = math.MaxUint32
+	}
+
+	return uint32(count), nil
+}
+
+// Returns the token ids of a criteria root, unknown roots have none
+func (s *dbOrderSource) GetCriteria(ctx context.Context, hash string) ([]string, error) {
+	root, err := parseBytes32(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.db.GetCriteria(ctx, root)
+	if errors.Is(err, db.ErrCriteriaNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(ids))
+	for i, id := range ids {
+		res[i] = id.String()
+	}
+
+	return res, nil
+}
+
+func (s *dbOrderSource) orderQuery(collection string, opts GetOrdersOpts) (db.OrderQuery, error) {
+	// Wire requests always ask for one side, listings when it is left out
+	side := opts.Side
+
+	q := db.OrderQuery{
+		ChainID: opts.ChainID,
+		Status:  db.StatusActive,
+		Side:    &side,
+		Sort:    opts.Sort,
+		Limit:   int(opts.Count),
+		Offset:  int(opts.Offset),
+	}
+
+	if q.ChainID == 0 {
+		q.ChainID = s.chainID
+	}
+
+	c, err := NormalizeCollection(collection)
+	if err != nil {
+		return q, err
+	}
+
+	if c != AllCollections {
+		q.Collection = common.HexToAddress(c)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,87 @@
+package node
+
+import (
+	"context"
+	"goport/db"
+	"goport/order"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Store reporting a fixed number of orders
+type countStore struct {
+	*db.MemoryStore
+	count int
+}
+
+func (s *countStore) CountOrders(ctx context.Context, q db.OrderQuery) (int, error) {
+	return s.count, nil
+}
+
+func TestGetOrderCountClamp(t *testing.T) {
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		count int
+		want  uint32
+	}{
+		{0, 0},
+		{12, 12},
+		{math.MaxUint32, math.MaxUint32},
+		{math.MaxUint32 + 1, math.MaxUint32},
+	} {
+		s := &dbOrderSource{db: &countStore{MemoryStore: db.NewMemoryStore(), count: c.count}, chainID: 1}
+
+		got, err := s.GetOrderCount(ctx, AllCollections, GetOrdersOpts{})
+		if err != nil {
+			t.Fatalf("GetOrderCount: %v", err)
+		}
+
+		if got != c.want {
+			t.Fatalf("count %d sent as %d, want %d", c.count, got, c.want)
+		}
+	}
+}
+
+func TestGetCriteria(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	s := &dbOrderSource{db: store, chainID: 1}
+
+	ids := []*big.Int{big.NewInt(3), big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 255)}
+	root := order.CriteriaRoot(ids)
+
+	if err := store.PutCriteria(ctx, root, ids); err != nil {
+		t.Fatalf("PutCriteria: %v", err)
+	}
+
+	got, err := s.GetCriteria(ctx, root.Hex())
+	if err != nil {
+		t.Fatalf("GetCriteria: %v", err)
+	}
+
+	want := []string{"3", "1", ids[2].String()}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("token ids = %v, want %v", got, want)
+	}
+
+	got, err = s.GetCriteria(ctx, common.HexToHash("0x01").Hex())
+	if err != nil {
+		t.Fatalf("GetCriteria of an unknown root: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("unknown root has token ids %v", got)
+	}
+
+	if _, err := s.GetCriteria(ctx, "0xzz"); err != ErrInvalidBytes32 {
+		t.Fatalf("GetCriteria of an invalid hash: got %v, want %v", err, ErrInvalidBytes32)
+	}
+
+	if err := store.PutCriteria(ctx, root, ids[:2]); err != db.ErrCriteriaMismatch {
+		t.Fatalf("PutCriteria of ids not matching the root: got %v, want %v", err, db.ErrCriteriaMismatch)
+	}
+}
@@ -0,0 +1,224 @@
+package node
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"goport/abi"
+	"goport/bus"
+	"goport/db"
+	"goport/order"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Chain on which every order is new and every ERC721 token is owned by owner and approved for
+// Seaport. Seaport calls are answered directly and token calls through Multicall3.
+type submitCaller struct {
+	owner common.Address
+}
+
+func (c *submitCaller) CodeAt(ctx context.Context, account common.Address, block *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *submitCaller) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	seaport, _ := abi.SeaportMetaData.GetAbi()
+	multicall, _ := abi.Multicall3MetaData.GetAbi()
+	erc721, _ := abi.ERC721MetaData.GetAbi()
+
+	if *call.To != order.Multicall3Address {
+		m, err := seaport.MethodById(call.Data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch m.Name {
+		case "getOrderStatus":
+			return m.Outputs.Pack(false, false, new(big.Int), new(big.Int))
+		case "getCounter":
+			return m.Outputs.Pack(new(big.Int))
+		}
+		return nil, errors.New("execution reverted")
+	}
+
+	in, err := multicall.Methods["aggregate3"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	batch := in[0].([]struct {
+		Target       common.Address `json:"target"`
+		AllowFailure bool           `json:"allowFailure"`
+		CallData     []byte         `json:"callData"`
+	})
+
+	results := make([]abi.Multicall3Result, len(batch))
+	for i, sub := range batch {
+		m, err := erc721.MethodById(sub.CallData[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		var data []byte
+		switch m.Name {
+		case "ownerOf":
+			data, err = m.Outputs.Pack(c.owner)
+		case "isApprovedForAll":
+			data, err = m.Outputs.Pack(true)
+		case "getApproved":
+			data, err = m.Outputs.Pack(common.Address{})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = abi.Multicall3Result{Success: data != nil, ReturnData: data}
+	}
+
+	return multicall.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+// Returns a listing of a token of testCollection by the owner of key, signed for the domain
+func signedListing(t *testing.T, key *ecdsa.PrivateKey, d order.Domain, endTime int64) (*abi.OrderComponents, []byte) {
+	t.Helper()
+
+	offerer := crypto.PubkeyToAddress(key.PublicKey)
+	c := &abi.OrderComponents{
+		Offerer: offerer,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC721, Token: common.HexToAddress(testCollection), IdentifierOrCriteria: big.NewInt(7), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(1e18), EndAmount: big.NewInt(1e18), Recipient: offerer},
+		},
+		OrderType: abi.OrderTypeFullOpen,
+		StartTime: big.NewInt(1),
+		EndTime:   big.NewInt(endTime),
+		Salt:      big.NewInt(1),
+		Counter:   new(big.Int),
+	}
+
+	digest := d.Digest(order.Hash(c))
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+
+	return c, sig
+}
+
+func TestSubmitOrder(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := order.NewDomain(big.NewInt(1), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+	v, err := order.NewValidator(&submitCaller{owner: crypto.PubkeyToAddress(key.PublicKey)}, d)
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+
+	ps, _ := newTestPubSub(t)
+	tm := NewTopicManager(1, ps, &sync.WaitGroup{}, nil, nil)
+
+	n := &Node{
+		Store:  db.NewMemoryStore(),
+		Bus:    bus.New(16),
+		Chains: map[int64]*Chain{1: {Topics: tm, Validators: order.Validators{v}}},
+	}
+	received := n.Bus.Subscribe(bus.Filter{ChainID: 1})
+
+	tm.mu.Lock()
+	topic, err := tm.topic(testCollection)
+	tm.mu.Unlock()
+	if err != nil {
+		t.Fatalf("topic: %v", err)
+	}
+	gossip, err := topic.Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	c, sig := signedListing(t, key, d, time.Now().Unix()+3600)
+	hash := order.Hash(c)
+
+	o, err := n.SubmitOrder(ctx, 1, c, sig)
+	if err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	if o.Hash != hash {
+		t.Fatalf("submitted order %s, want %s", o.Hash.Hex(), hash.Hex())
+	}
+
+	if _, err := n.Store.GetOrder(ctx, 1, hash); err != nil {
+		t.Fatalf("GetOrder of the submitted order: %v", err)
+	}
+
+	select {
+	case m := <-received.Messages():
+		if m.Type != bus.TypeOrder || m.Order.Hash != hash {
+			t.Fatalf("bus got a %s message of %s, want the submitted order", m.Type, m.Order.Hash.Hex())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("submitted order was not published on the bus")
+	}
+
+	// The order is gossiped on the topic of its collection
+	timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	msg, err := gossip.Next(timeout)
+	if err != nil {
+		t.Fatalf("order was not gossiped on the collection topic: %v", err)
+	}
+
+	j, err := DecodeOrder(msg.Data)
+	if err != nil {
+		t.Fatalf("DecodeOrder: %v", err)
+	}
+	gc, err := j.Components()
+	if err != nil {
+		t.Fatalf("Components: %v", err)
+	}
+	if order.Hash(gc) != hash {
+		t.Fatalf("gossiped order %s, want %s", order.Hash(gc).Hex(), hash.Hex())
+	}
+
+	// Expired orders cannot be fixed by the offerer and are rejected
+	expired, sig := signedListing(t, key, d, 2)
+
+	var verr *order.ValidationError
+	if _, err := n.SubmitOrder(ctx, 1, expired, sig); !errors.As(err, &verr) {
+		t.Fatalf("SubmitOrder of an expired order = %v, want a ValidationError", err)
+	}
+	if _, err := n.Store.GetOrder(ctx, 1, order.Hash(expired)); err == nil {
+		t.Fatal("rejected order was stored")
+	}
+
+	// Orders signed by another account are rejected too
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := signedListing(t, key, d, time.Now().Unix()+3600)
+	forged.Salt = big.NewInt(2)
+	_, sig = signedListing(t, other, d, time.Now().Unix()+3600)
+	if _, err := n.SubmitOrder(ctx, 1, forged, sig); !errors.As(err, &verr) {
+		t.Fatalf("SubmitOrder with a signature of another account = %v, want a ValidationError", err)
+	}
+
+	if _, err := n.SubmitOrder(ctx, 10, c, sig); !errors.Is(err, order.ErrNoDeployment) {
+		t.Fatalf("SubmitOrder on a chain that is not followed = %v, want ErrNoDeployment", err)
+	}
+}
@@ -0,0 +1,272 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// Prefix of every order gossip topic, followed by the chain id and the collection
+const topicPrefix = "/seaport-gossip/0.0.1/orders/"
+
+// Collection that subscribes to the orders of all collections
+const AllCollections = "*"
+
+// Attempts and time between attempts to close the topic of a collection left
+const (
+	closeAttempts      = 10
+	closeRetryInterval = 50 * time.Millisecond
+)
+
+var (
+	ErrInvalidCollection = errors.New("invalid collection address")
+	ErrNotJoined         = errors.New("collection topic not joined")
+)
+
+// Manages the per-collection gossipsub topics of a chain the node is subscribed to
+type TopicManager struct {
+	ChainID int64
+
+	ps        *pubsub.PubSub
+	wg        *sync.WaitGroup
+	handler   func(*pubsub.Subscription)
+	validator pubsub.ValidatorEx
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+	subs   map[string]*pubsub.Subscription
+}
+
+// Creates a new TopicManager, handler is run in its own goroutine for every joined topic and
+// validator decides which messages are delivered to it and forwarded to other peers
+func NewTopicManager(chainID int64, ps *pubsub.PubSub, wg *sync.WaitGroup, handler func(*pubsub.Subscription), validator pubsub.ValidatorEx) *TopicManager {
+	return &TopicManager{
+		ChainID:   chainID,
+		ps:        ps,
+		wg:        wg,
+		handler:   handler,
+		validator: validator,
+		topics:    make(map[string]*pubsub.Topic),
+		subs:      make(map[string]*pubsub.Subscription),
+	}
+}
+
+// Returns the checksummed collection address, or AllCollections for the wildcard
+func NormalizeCollection(collection string) (string, error) {
+	collection = strings.TrimSpace(collection)
+	if collection == AllCollections {
+		return AllCollections, nil
+	}
+
+	if !common.IsHexAddress(collection) {
+		return "", ErrInvalidCollection
+	}
+
+	return common.HexToAddress(collection).Hex(), nil
+}
+
+// Returns the gossipsub topic name of a collection on a chain
+func TopicName(chainID int64, collection string) string {
+	prefix := topicPrefix + strconv.FormatInt(chainID, 10) + "/"
+
+	if collection == AllCollections {
+		return prefix + AllCollections
+	}
+
+	return prefix + strings.ToLower(collection)
+}
+
+// Subscribes to the order topic of a collection
+func (tm *TopicManager) Join(collection string) error {
+	collection, err := NormalizeCollection(collection)
+	if err != nil {
+		return err
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, ok := tm.subs[collection]; ok {
+		return nil
+	}
+
+	t, err := tm.topic(collection)
+	if err != nil {
+		return err
+	}
+
+	sub, err := t.Subscribe()
+	if err != nil {
+		return err
+	}
+	tm.subs[collection] = sub
+
+	tm.wg.Add(1)
+	go func() {
+		defer tm.wg.Done()
+		tm.handler(sub)
+	}()
+
+	log.Printf("Joined topic %s", t.String())
+
+	return nil
+}
+
+// Unsubscribes from the order topic of a collection
+func (tm *TopicManager) Leave(collection string) error {
+	collection, err := NormalizeCollection(collection)
+	if err != nil {
+		return err
+	}
+
+	tm.mu.Lock()
+	sub, ok := tm.subs[collection]
+	if !ok {
+		tm.mu.Unlock()
+		return ErrNotJoined
+	}
+
+	sub.Cancel()
+	delete(tm.subs, collection)
+	tm.mu.Unlock()
+
+	// Cancel returns before pubsub removes the subscription, the topic cannot be closed until it has
+	if err := tm.closeTopic(collection); err != nil {
+		log.Printf("Failed to close topic %s: %v", TopicName(tm.ChainID, collection), err.Error())
+		return err
+	}
+
+	log.Printf("Left topic %s", TopicName(tm.ChainID, collection))
+
+	return nil
+}
+
+// Returns the collections the node is subscribed to
+func (tm *TopicManager) Collections() []string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	collections := make([]string, 0, len(tm.subs))
+	for c := range tm.subs {
+		collections = append(collections, c)
+	}
+
+	return collections
+}
+
+// Returns true if the node receives the orders of the collection
+func (tm *TopicManager) Subscribed(collection string) bool {
+	collection, err := NormalizeCollection(collection)
+	if err != nil {
+		return false
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	_, all := tm.subs[AllCollections]
+	_, ok := tm.subs[collection]
+
+	return all || ok
+}
+
+// Publishes data to the topic of the collection and to the wildcard topic. Nodes subscribed to both
+// receive the order twice, the copy is dropped by handleSub.
+func (tm *TopicManager) Publish(ctx context.Context, collection string, data []byte) error {
+	collection, err := NormalizeCollection(collection)
+	if err != nil {
+		return err
+	}
+
+	collections := []string{collection}
+	if collection != AllCollections {
+		collections = append(collections, AllCollections)
+	}
+
+	// Publishing can block on the pubsub event loop, so it is done without holding mu
+	topics := make([]*pubsub.Topic, 0, len(collections))
+
+	tm.mu.Lock()
+	for _, c := range collections {
+		t, err := tm.topic(c)
+		if err != nil {
+			tm.mu.Unlock()
+			return err
+		}
+
+		topics = append(topics, t)
+	}
+	tm.mu.Unlock()
+
+	for _, t := range topics {
+		if err := t.Publish(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Closes the topic of a collection left, retrying while pubsub is still removing its cancelled
+// subscription. mu is only held during each attempt so the node keeps handling its other topics.
+// If the collection is joined again in the meantime the topic is kept open.
+func (tm *TopicManager) closeTopic(collection string) error {
+	var err error
+
+	for i := 0; i < closeAttempts; i++ {
+		if i > 0 {
+			time.Sleep(closeRetryInterval)
+		}
+
+		tm.mu.Lock()
+
+		t, open := tm.topics[collection]
+		_, joined := tm.subs[collection]
+		if !open || joined {
+			tm.mu.Unlock()
+			return nil
+		}
+
+		if err = t.Close(); err == nil {
+			delete(tm.topics, collection)
+			tm.ps.UnregisterTopicValidator(TopicName(tm.ChainID, collection))
+		}
+
+		tm.mu.Unlock()
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Returns the topic handle of a collection, joining it if needed. Must be called with mu held
+func (tm *TopicManager) topic(collection string) (*pubsub.Topic, error) {
+	if t, ok := tm.topics[collection]; ok {
+		return t, nil
+	}
+
+	if tm.validator != nil {
+		if err := tm.ps.RegisterTopicValidator(TopicName(tm.ChainID, collection), tm.validator); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := tm.ps.Join(TopicName(tm.ChainID, collection))
+	if err != nil {
+		tm.ps.UnregisterTopicValidator(TopicName(tm.ChainID, collection))
+		return nil, err
+	}
+	tm.topics[collection] = t
+
+	return t, nil
+}
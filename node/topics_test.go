@@ -0,0 +1,175 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const testCollection = "0x8a90CAb2b38dba80c64b7734e58Ee1dB38B8992e"
+
+func newTestPubSub(t *testing.T) (*pubsub.PubSub, peer.ID) {
+	t.Helper()
+
+	h, err := libp2p.New(libp2p.NoListenAddrs)
+	if err != nil {
+		t.Fatalf("libp2p.New: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ps, err := pubsub.NewGossipSub(ctx, h)
+	if err != nil {
+		t.Fatalf("NewGossipSub: %v", err)
+	}
+
+	return ps, h.ID()
+}
+
+func TestTopicManagerJoinLeave(t *testing.T) {
+	ps, _ := newTestPubSub(t)
+	wg := &sync.WaitGroup{}
+
+	tm := NewTopicManager(1, ps, wg, func(sub *pubsub.Subscription) {
+		for {
+			if _, err := sub.Next(context.Background()); err != nil {
+				return
+			}
+		}
+	}, nil)
+
+	for i := 0; i < 3; i++ {
+		if err := tm.Join(testCollection); err != nil {
+			t.Fatalf("Join: %v", err)
+		}
+
+		if !tm.Subscribed(testCollection) {
+			t.Fatal("not subscribed after Join")
+		}
+
+		if err := tm.Leave(testCollection); err != nil {
+			t.Fatalf("Leave: %v", err)
+		}
+
+		if tm.Subscribed(testCollection) {
+			t.Fatal("subscribed after Leave")
+		}
+
+		// The topic must be closed so it can be joined again
+		if len(tm.topics) != 0 {
+			t.Fatalf("%d topics still open after Leave", len(tm.topics))
+		}
+	}
+
+	if err := tm.Leave(testCollection); err != ErrNotJoined {
+		t.Fatalf("Leave of a topic not joined: got %v, want %v", err, ErrNotJoined)
+	}
+
+	wg.Wait()
+}
+
+func TestTopicManagerLeaveReleasesLock(t *testing.T) {
+	ps, _ := newTestPubSub(t)
+	wg := &sync.WaitGroup{}
+
+	tm := NewTopicManager(1, ps, wg, func(sub *pubsub.Subscription) {
+		for {
+			if _, err := sub.Next(context.Background()); err != nil {
+				return
+			}
+		}
+	}, nil)
+
+	if err := tm.Join(testCollection); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+
+	// Another subscription keeps the topic open, so Leave retries closing it until it gives up
+	other, err := tm.topics[testCollection].Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	left := make(chan error, 1)
+	go func() { left <- tm.Leave(testCollection) }()
+
+	// The other topics stay usable while Leave waits
+	time.Sleep(2 * closeRetryInterval)
+
+	unlocked := make(chan struct{})
+	go func() {
+		tm.Subscribed(AllCollections)
+		close(unlocked)
+	}()
+
+	select {
+	case <-unlocked:
+	case <-time.After(2 * closeRetryInterval):
+		t.Fatal("Leave holds the lock while it retries")
+	}
+
+	if err := <-left; err == nil {
+		t.Fatal("Leave closed a topic with a subscription")
+	}
+
+	other.Cancel()
+	wg.Wait()
+}
+
+func TestTopicManagerPublish(t *testing.T) {
+	ps, self := newTestPubSub(t)
+	wg := &sync.WaitGroup{}
+
+	received := make(chan *pubsub.Message, 2)
+	tm := NewTopicManager(1, ps, wg, func(sub *pubsub.Subscription) {
+		for {
+			msg, err := sub.Next(context.Background())
+			if err != nil {
+				return
+			}
+			received <- msg
+		}
+	}, nil)
+
+	if err := tm.Join(AllCollections); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+
+	// Orders of a collection are also published to the wildcard topic
+	if err := tm.Publish(context.Background(), testCollection, []byte("order")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if string(msg.Data) != "order" || msg.ReceivedFrom != self {
+			t.Fatalf("received %q from %v", msg.Data, msg.ReceivedFrom)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("published order was not received on the wildcard topic")
+	}
+
+	if err := tm.Leave(AllCollections); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+
+	wg.Wait()
+}
+
+func TestOrderValidatorSelf(t *testing.T) {
+	_, self := newTestPubSub(t)
+
+	// Orders published by self were validated when they were submitted, the validator must not decode
+	// them again, it would panic without a listener
+	v := orderValidator(self, nil, nil)
+	if res := v(context.Background(), self, &pubsub.Message{}); res != pubsub.ValidationAccept {
+		t.Fatalf("order published by self: got %v, want accept", res)
+	}
+}
@@ -0,0 +1,295 @@
+package order
+
+import (
+	"context"
+	"goport/abi"
+	"math/big"
+	"sync"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address of the Multicall3 contract, deployed at the same address on every chain
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const (
+	CodeInvalidBalance  ErrorCode = "invalid-balance"
+	CodeInvalidApproval ErrorCode = "invalid-approval"
+)
+
+// Returns true if a check failed that the offerer cannot fix by topping up their balance or approvals
+func (r *ValidationResult) Fatal() bool {
+	for _, c := range r.Errors {
+		if c != CodeInvalidBalance && c != CodeInvalidApproval {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	erc20ABI   = mustParseABI(abi.ERC20MetaData)
+	erc721ABI  = mustParseABI(abi.ERC721MetaData)
+	erc1155ABI = mustParseABI(abi.ERC1155MetaData)
+)
+
+func mustParseABI(m *bind.MetaData) *ethabi.ABI {
+	a, err := m.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+// Resolves conduit keys to the operator that transfers the offerer's tokens
+type conduitResolver struct {
+	seaport abi.SeaportReader
+	caller  bind.ContractCaller
+
+	mu         sync.Mutex
+	controller *abi.ConduitControllerCaller
+	conduits   map[[32]byte]common.Address
+}
+
+// Returns the address that must be approved to transfer items of an order with the given conduit key.
+// Orders without a conduit key are transferred by Seaport itself.
+func (cr *conduitResolver) operator(ctx context.Context, seaport common.Address, key [32]byte) (common.Address, error) {
+	if key == ([32]byte{}) {
+		return seaport, nil
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if conduit, ok := cr.conduits[key]; ok {
+		return conduit, nil
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	if cr.controller == nil {
+		info, err := cr.seaport.Information(opts)
+		if err != nil {
+			return common.Address{}, err
+		}
+
+		cr.controller, err = abi.NewConduitControllerCaller(info.ConduitController, cr.caller)
+		if err != nil {
+			return common.Address{}, err
+		}
+	}
+
+	res, err := cr.controller.GetConduit(opts, key)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	// Conduits are deployed with CREATE2, so the address is known even before deployment
+	cr.conduits[key] = res.Conduit
+
+	return res.Conduit, nil
+}
+
+// Single call batched through Multicall3 and the check run on its result
+type balanceCall struct {
+	call     abi.Multicall3Call3
+	check    func(out []interface{}) bool
+	contract *ethabi.ABI
+	name     string
+}
+
+// Check of an order that passes if any of its calls passes, e.g. an ERC721 token is approved either
+// for all of the offerer's tokens or by itself
+type requirement struct {
+	code  ErrorCode
+	calls []int
+}
+
+// Checks the offerer owns and approved every offer item, in a single Multicall3 call
+func (v *Validator) validateBalances(ctx context.Context, r *ValidationResult, c *abi.OrderComponents) error {
+	operator, err := v.conduits.operator(ctx, v.Domain.VerifyingContract, c.ConduitKey)
+	if err != nil {
+		return err
+	}
+
+	now := big.NewInt(v.Now().Unix())
+
+	calls := []balanceCall{}
+	requirements := []requirement{}
+
+	add := func(call balanceCall) int {
+		calls = append(calls, call)
+		return len(calls) - 1
+	}
+	require := func(code ErrorCode, calls ...int) {
+		requirements = append(requirements, requirement{code: code, calls: calls})
+	}
+
+	// Amounts of the same fungible token are summed across offer items
+	erc20 := make(map[common.Address]*big.Int)
+	erc1155 := make(map[common.Address]map[string]*big.Int)
+
+	// Tokens that must be approved for all, and ERC721 tokens that can also be approved one by one
+	approvals := make(map[common.Address]bool)
+	erc721 := make(map[common.Address][]*big.Int)
+
+	for _, item := range c.Offer {
+		amount := CurrentAmount(item.StartAmount, item.EndAmount, c.StartTime, c.EndTime, now)
+
+		switch item.ItemType {
+		case abi.ItemTypeERC20:
+			if erc20[item.Token] == nil {
+				erc20[item.Token] = new(big.Int)
+			}
+			erc20[item.Token].Add(erc20[item.Token], amount)
+
+		case abi.ItemTypeERC721:
+			require(CodeInvalidBalance, add(v.newCall(item.Token, erc721ABI, "ownerOf", func(out []interface{}) bool {
+				return out[0].(common.Address) == c.Offerer
+			}, item.IdentifierOrCriteria)))
+			erc721[item.Token] = append(erc721[item.Token], item.IdentifierOrCriteria)
+
+		case abi.ItemTypeERC1155:
+			if erc1155[item.Token] == nil {
+				erc1155[item.Token] = make(map[string]*big.Int)
+			}
+			id := item.IdentifierOrCriteria.String()
+			if erc1155[item.Token][id] == nil {
+				erc1155[item.Token][id] = new(big.Int)
+			}
+			erc1155[item.Token][id].Add(erc1155[item.Token][id], amount)
+
+		case abi.ItemTypeERC721WithCriteria, abi.ItemTypeERC1155WithCriteria:
+			// The token id is only known on fulfillment, so only the approval for all can be checked
+			approvals[item.Token] = true
+		}
+	}
+
+	for token, amount := range erc20 {
+		amount := amount
+		require(CodeInvalidBalance, add(v.newCall(token, erc20ABI, "balanceOf", func(out []interface{}) bool {
+			return out[0].(*big.Int).Cmp(amount) >= 0
+		}, c.Offerer)))
+		require(CodeInvalidApproval, add(v.newCall(token, erc20ABI, "allowance", func(out []interface{}) bool {
+			return out[0].(*big.Int).Cmp(amount) >= 0
+		}, c.Offerer, operator)))
+	}
+
+	for token, ids := range erc1155 {
+		for id, amount := range ids {
+			amount := amount
+			tokenID, _ := new(big.Int).SetString(id, 10)
+			require(CodeInvalidBalance, add(v.newCall(token, erc1155ABI, "balanceOf", func(out []interface{}) bool {
+				return out[0].(*big.Int).Cmp(amount) >= 0
+			}, c.Offerer, tokenID)))
+		}
+		approvals[token] = true
+	}
+
+	// ERC721 and ERC1155 share the isApprovedForAll signature
+	forAll := make(map[common.Address]int)
+	approvedForAll := func(token common.Address) int {
+		if i, ok := forAll[token]; ok {
+			return i
+		}
+
+		forAll[token] = add(v.newCall(token, erc721ABI, "isApprovedForAll", func(out []interface{}) bool {
+			return out[0].(bool)
+		}, c.Offerer, operator))
+
+		return forAll[token]
+	}
+
+	for token := range approvals {
+		require(CodeInvalidApproval, approvedForAll(token))
+	}
+
+	// An ERC721 token approved by itself can be transferred even if the collection is not approved
+	for token, ids := range erc721 {
+		for _, id := range ids {
+			require(CodeInvalidApproval, approvedForAll(token), add(v.newCall(token, erc721ABI, "getApproved", func(out []interface{}) bool {
+				return out[0].(common.Address) == operator
+			}, id)))
+		}
+	}
+
+	if len(calls) == 0 {
+		return nil
+	}
+
+	batch := make([]abi.Multicall3Call3, len(calls))
+	for i, call := range calls {
+		batch[i] = call.call
+	}
+
+	results, err := v.multicall.Aggregate3(&bind.CallOpts{Context: ctx}, batch)
+	if err != nil {
+		return err
+	}
+
+	passed := make([]bool, len(calls))
+	for i, res := range results {
+		if !res.Success || i >= len(calls) {
+			continue
+		}
+
+		out, err := calls[i].contract.Unpack(calls[i].name, res.ReturnData)
+		passed[i] = err == nil && len(out) > 0 && calls[i].check(out)
+	}
+
+	for _, req := range requirements {
+		met := false
+		for _, i := range req.calls {
+			met = met || passed[i]
+		}
+
+		if !met {
+			r.add(req.code)
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) newCall(target common.Address, a *ethabi.ABI, name string, check func([]interface{}) bool, args ...interface{}) balanceCall {
+	// Packing only fails on argument type mismatches, which are fixed above
+	data, _ := a.Pack(name, args...)
+
+	return balanceCall{
+		call: abi.Multicall3Call3{
+			Target:       target,
+			AllowFailure: true,
+			CallData:     data,
+		},
+		check:    check,
+		contract: a,
+		name:     name,
+	}
+}
+
+// Returns the amount of an item at the given time, linearly interpolated between its start and end amount
+func CurrentAmount(startAmount *big.Int, endAmount *big.Int, startTime *big.Int, endTime *big.Int, now *big.Int) *big.Int {
+	if startAmount.Cmp(endAmount) == 0 {
+		return new(big.Int).Set(startAmount)
+	}
+
+	duration := new(big.Int).Sub(endTime, startTime)
+	if duration.Sign() <= 0 || now.Cmp(startTime) <= 0 {
+		return new(big.Int).Set(startAmount)
+	}
+	if now.Cmp(endTime) >= 0 {
+		return new(big.Int).Set(endAmount)
+	}
+
+	elapsed := new(big.Int).Sub(now, startTime)
+	remaining := new(big.Int).Sub(duration, elapsed)
+
+	total := new(big.Int).Mul(startAmount, remaining)
+	total.Add(total, new(big.Int).Mul(endAmount, elapsed))
+
+	return total.Div(total, duration)
+}
@@ -0,0 +1,268 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Contracts whose calls multicallCaller answers
+var testABIs = []*ethabi.ABI{
+	erc721ABI,
+	erc20ABI,
+	erc1155ABI,
+	mustParseABI(abi.SeaportMetaData),
+	mustParseABI(abi.ConduitControllerMetaData),
+}
+
+// Returns the method called by data, looked up in every contract of the tests
+func testMethod(data []byte) (*ethabi.Method, []interface{}, error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("no selector")
+	}
+
+	for _, a := range testABIs {
+		m, err := a.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+
+		args, err := m.Inputs.Unpack(data[4:])
+		return m, args, err
+	}
+
+	return nil, nil, errors.New("unknown method")
+}
+
+// Chain whose calls, on their own or batched through Multicall3, are answered by reply with the
+// outputs of the method, or nil to revert
+type multicallCaller struct {
+	reply func(method string, args []interface{}) []interface{}
+
+	// Methods of the last batch
+	methods []string
+}
+
+func (c *multicallCaller) CodeAt(ctx context.Context, account common.Address, block *big.Int) ([]byte, error) {
+	return []byte{0}, nil
+}
+
+func (c *multicallCaller) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	multicall, err := abi.Multicall3MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	if call.To == nil || len(call.Data) < 4 {
+		return nil, errors.New("no call")
+	}
+
+	if *call.To != Multicall3Address {
+		m, args, err := testMethod(call.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		out := c.reply(m.Name, args)
+		if out == nil {
+			return nil, errors.New("execution reverted")
+		}
+
+		return m.Outputs.Pack(out...)
+	}
+
+	in, err := multicall.Methods["aggregate3"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	batch := in[0].([]struct {
+		Target       common.Address `json:"target"`
+		AllowFailure bool           `json:"allowFailure"`
+		CallData     []byte         `json:"callData"`
+	})
+
+	c.methods = nil
+	results := make([]abi.Multicall3Result, len(batch))
+
+	for i, sub := range batch {
+		m, args, err := testMethod(sub.CallData)
+		if err != nil {
+			return nil, err
+		}
+		c.methods = append(c.methods, m.Name)
+
+		out := c.reply(m.Name, args)
+		if out == nil {
+			continue
+		}
+
+		data, err := m.Outputs.Pack(out...)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = abi.Multicall3Result{Success: true, ReturnData: data}
+	}
+
+	return multicall.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func TestValidateERC721Approvals(t *testing.T) {
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	collection := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000bad")
+
+	d := NewDomain(big.NewInt(1), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+	seaport := d.VerifyingContract
+
+	listing := func(itemType uint8) *abi.OrderComponents {
+		return &abi.OrderComponents{
+			Offerer: offerer,
+			Offer:   []abi.OfferItem{{ItemType: itemType, Token: collection, IdentifierOrCriteria: big.NewInt(7), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)}},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		item     uint8
+		owner    common.Address
+		forAll   bool
+		approved common.Address
+		want     []ErrorCode
+	}{
+		{"approved for all", abi.ItemTypeERC721, offerer, true, common.Address{}, nil},
+		{"token approved", abi.ItemTypeERC721, offerer, false, seaport, nil},
+		{"token approved to another operator", abi.ItemTypeERC721, offerer, false, other, []ErrorCode{CodeInvalidApproval}},
+		{"not owned", abi.ItemTypeERC721, other, true, common.Address{}, []ErrorCode{CodeInvalidBalance}},
+		{"criteria without approval for all", abi.ItemTypeERC721WithCriteria, offerer, false, seaport, []ErrorCode{CodeInvalidApproval}},
+		{"criteria approved for all", abi.ItemTypeERC721WithCriteria, offerer, true, common.Address{}, nil},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		caller := &multicallCaller{reply: func(method string, args []interface{}) []interface{} {
+			switch method {
+			case "ownerOf":
+				return []interface{}{tc.owner}
+			case "isApprovedForAll":
+				return []interface{}{tc.forAll && args[1].(common.Address) == seaport}
+			case "getApproved":
+				return []interface{}{tc.approved}
+			}
+			return nil
+		}}
+
+		v, err := NewValidator(caller, d)
+		if err != nil {
+			t.Fatalf("NewValidator: %v", err)
+		}
+
+		r := &ValidationResult{}
+		if err := v.validateBalances(context.Background(), r, listing(tc.item)); err != nil {
+			t.Fatalf("%s: validateBalances: %v", tc.name, err)
+		}
+
+		if len(r.Errors) != len(tc.want) || (len(tc.want) > 0 && r.Errors[0] != tc.want[0]) {
+			t.Errorf("%s: errors %v, want %v", tc.name, r.Errors, tc.want)
+		}
+
+		// Approvals of single tokens are fetched in the same batch
+		if tc.item == abi.ItemTypeERC721 && len(caller.methods) != 3 {
+			t.Errorf("%s: batch of %v, want ownerOf, isApprovedForAll and getApproved", tc.name, caller.methods)
+		}
+	}
+}
+
+func TestValidateFungibleBalances(t *testing.T) {
+	offerer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	token := common.HexToAddress("0x0000000000000000000000000000000000000e20")
+	multi := common.HexToAddress("0x0000000000000000000000000000000001155000")
+	controller := common.HexToAddress("0x00000000F9490004C11Cef243f5400493c00Ad63")
+	conduit := common.HexToAddress("0x1E0049783F008A0085193E00003D00cd54003c71")
+
+	d := NewDomain(big.NewInt(1), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+	seaport := d.VerifyingContract
+
+	// 60 + 40 of the ERC20 token and 2 of the ERC1155 token, transferred through a conduit
+	order := &abi.OrderComponents{
+		Offerer:    offerer,
+		ConduitKey: [32]byte{1},
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC20, Token: token, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(60), EndAmount: big.NewInt(60)},
+			{ItemType: abi.ItemTypeERC20, Token: token, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(40), EndAmount: big.NewInt(40)},
+			{ItemType: abi.ItemTypeERC1155, Token: multi, IdentifierOrCriteria: big.NewInt(3), StartAmount: big.NewInt(2), EndAmount: big.NewInt(2)},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		balance   int64
+		allowance int64
+		spender   common.Address
+		held      int64
+		forAll    common.Address
+		want      []ErrorCode
+	}{
+		{"funded and approved", 100, 100, conduit, 2, conduit, nil},
+		{"balance below the summed amounts", 99, 100, conduit, 2, conduit, []ErrorCode{CodeInvalidBalance}},
+		{"allowance below the summed amounts", 100, 60, conduit, 2, conduit, []ErrorCode{CodeInvalidApproval}},
+		{"allowance to Seaport instead of the conduit", 100, 100, seaport, 2, conduit, []ErrorCode{CodeInvalidApproval}},
+		{"ERC1155 balance too low", 100, 100, conduit, 1, conduit, []ErrorCode{CodeInvalidBalance}},
+		{"ERC1155 not approved for the conduit", 100, 100, conduit, 2, seaport, []ErrorCode{CodeInvalidApproval}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		caller := &multicallCaller{reply: func(method string, args []interface{}) []interface{} {
+			switch method {
+			case "information":
+				return []interface{}{"1.5", [32]byte{}, controller}
+			case "getConduit":
+				return []interface{}{conduit, true}
+			case "balanceOf":
+				if len(args) == 2 {
+					if args[1].(*big.Int).Int64() != 3 {
+						return []interface{}{new(big.Int)}
+					}
+					return []interface{}{big.NewInt(tc.held)}
+				}
+				return []interface{}{big.NewInt(tc.balance)}
+			case "allowance":
+				if args[1].(common.Address) != tc.spender {
+					return []interface{}{new(big.Int)}
+				}
+				return []interface{}{big.NewInt(tc.allowance)}
+			case "isApprovedForAll":
+				return []interface{}{args[1].(common.Address) == tc.forAll}
+			}
+			return nil
+		}}
+
+		v, err := NewValidator(caller, d)
+		if err != nil {
+			t.Fatalf("NewValidator: %v", err)
+		}
+
+		r := &ValidationResult{}
+		if err := v.validateBalances(context.Background(), r, order); err != nil {
+			t.Fatalf("%s: validateBalances: %v", tc.name, err)
+		}
+
+		if len(r.Errors) != len(tc.want) || (len(tc.want) > 0 && r.Errors[0] != tc.want[0]) {
+			t.Errorf("%s: errors %v, want %v", tc.name, r.Errors, tc.want)
+		}
+
+		// One balance and one allowance for the summed ERC20 items, one balance and one approval for the ERC1155 item
+		if len(caller.methods) != 4 {
+			t.Errorf("%s: batch of %v, want 4 calls", tc.name, caller.methods)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package order
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Returns the merkle root of the token ids of a criteria item, built like seaport-js does: the leaves
+// are the hashes of the 32 byte ids, sorted, pairs are sorted before they are hashed and the last node
+// of an odd layer moves up unchanged. Seaport verifies proofs against this root when the order is
+// fulfilled. The root of no ids is zero, which Seaport treats as any token of the collection.
+func CriteriaRoot(tokenIDs []*big.Int) common.Hash {
+	if len(tokenIDs) == 0 {
+		return common.Hash{}
+	}
+
+	layer := make([][]byte, len(tokenIDs))
+	for i, id := range tokenIDs {
+		layer[i] = crypto.Keccak256(math.U256Bytes(new(big.Int).Set(id)))
+	}
+
+	sort.Slice(layer, func(i, j int) bool {
+		return bytes.Compare(layer[i], layer[j]) < 0
+	})
+
+	for len(layer) > 1 {
+		next := make([][]byte, 0, (len(layer)+1)/2)
+
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+
+			a, b := layer[i], layer[i+1]
+			if bytes.Compare(a, b) > 0 {
+				a, b = b, a
+			}
+
+			next = append(next, crypto.Keccak256(a, b))
+		}
+
+		layer = next
+	}
+
+	return common.BytesToHash(layer[0])
+}
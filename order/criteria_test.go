@@ -0,0 +1,125 @@
+package order
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func leaf(id int64) []byte {
+	return crypto.Keccak256(math.U256Bytes(big.NewInt(id)))
+}
+
+func hashPair(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+
+	return crypto.Keccak256(a, b)
+}
+
+// Verifies a proof like Seaport's CriteriaResolution does
+func verifyProof(root common.Hash, id int64, proof [][]byte) bool {
+	h := leaf(id)
+	for _, p := range proof {
+		h = hashPair(h, p)
+	}
+
+	return common.BytesToHash(h) == root
+}
+
+func ids(vs ...int64) []*big.Int {
+	res := make([]*big.Int, len(vs))
+	for i, v := range vs {
+		res[i] = big.NewInt(v)
+	}
+
+	return res
+}
+
+func TestCriteriaRoot(t *testing.T) {
+	if root := CriteriaRoot(nil); root != (common.Hash{}) {
+		t.Fatalf("root of no ids = %s, want zero", root.Hex())
+	}
+
+	if root, want := CriteriaRoot(ids(7)), common.BytesToHash(leaf(7)); root != want {
+		t.Fatalf("root of one id = %s, want %s", root.Hex(), want.Hex())
+	}
+
+	if root, want := CriteriaRoot(ids(1, 2)), common.BytesToHash(hashPair(leaf(1), leaf(2))); root != want {
+		t.Fatalf("root of two ids = %s, want %s", root.Hex(), want.Hex())
+	}
+}
+
+func TestCriteriaRootOrder(t *testing.T) {
+	a := CriteriaRoot(ids(1, 2, 3, 4, 5))
+	b := CriteriaRoot(ids(5, 3, 1, 4, 2))
+
+	if a != b {
+		t.Fatalf("roots of the same ids differ: %s != %s", a.Hex(), b.Hex())
+	}
+
+	if c := CriteriaRoot(ids(1, 2, 3, 4, 6)); c == a {
+		t.Fatal("roots of different ids are equal")
+	}
+}
+
+func TestCriteriaRootProofs(t *testing.T) {
+	// Odd layers carry their last node up, so every id has a proof of at most ceil(log2(n)) hashes
+	vs := []int64{10, 20, 30, 40, 50}
+	root := CriteriaRoot(ids(vs...))
+
+	layer := make([][]byte, len(vs))
+	for i, v := range vs {
+		layer[i] = leaf(v)
+	}
+	sort.Slice(layer, func(i, j int) bool {
+		return bytes.Compare(layer[i], layer[j]) < 0
+	})
+
+	for _, v := range vs {
+		if !verifyProof(root, v, proof(layer, leaf(v))) {
+			t.Fatalf("proof of %d does not verify against %s", v, root.Hex())
+		}
+	}
+
+	if verifyProof(root, 60, proof(layer, leaf(10))) {
+		t.Fatal("proof of an id outside the criteria verifies")
+	}
+}
+
+// Returns the proof of a leaf of the sorted leaves
+func proof(layer [][]byte, h []byte) [][]byte {
+	var res [][]byte
+
+	for len(layer) > 1 {
+		next := make([][]byte, 0, (len(layer)+1)/2)
+
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+
+			switch {
+			case bytes.Equal(layer[i], h):
+				res = append(res, layer[i+1])
+				h = hashPair(layer[i], layer[i+1])
+			case bytes.Equal(layer[i+1], h):
+				res = append(res, layer[i])
+				h = hashPair(layer[i], layer[i+1])
+			}
+
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+
+		layer = next
+	}
+
+	return res
+}
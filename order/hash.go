@@ -0,0 +1,127 @@
+package order
+
+import (
+	"goport/abi"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP-712 domain name of every Seaport version
+const SeaportName = "Seaport"
+
+const (
+	offerItemType         = "OfferItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount,uint256 endAmount)"
+	considerationItemType = "ConsiderationItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount,uint256 endAmount,address recipient)"
+	orderComponentsType   = "OrderComponents(address offerer,address zone,OfferItem[] offer,ConsiderationItem[] consideration,uint8 orderType,uint256 startTime,uint256 endTime,bytes32 zoneHash,uint256 salt,bytes32 conduitKey,uint256 counter)"
+	domainType            = "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+)
+
+var (
+	OfferItemTypeHash         = crypto.Keccak256Hash([]byte(offerItemType))
+	ConsiderationItemTypeHash = crypto.Keccak256Hash([]byte(considerationItemType))
+	// Referenced struct types are appended in alphabetical order as required by EIP-712
+	OrderTypeHash  = crypto.Keccak256Hash([]byte(orderComponentsType + considerationItemType + offerItemType))
+	DomainTypeHash = crypto.Keccak256Hash([]byte(domainType))
+)
+
+// EIP-712 domain orders are signed for
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract common.Address
+}
+
+// Returns the domain of a Seaport deployment, the version is also the EIP-712 domain version
+func NewDomain(chainID *big.Int, version string, verifyingContract common.Address) Domain {
+	return Domain{
+		Name:              SeaportName,
+		Version:           version,
+		ChainID:           chainID,
+		VerifyingContract: verifyingContract,
+	}
+}
+
+// Returns the EIP-712 domain separator
+func (d Domain) Separator() common.Hash {
+	return crypto.Keccak256Hash(
+		DomainTypeHash[:],
+		crypto.Keccak256([]byte(d.Name)),
+		crypto.Keccak256([]byte(d.Version)),
+		word(d.ChainID),
+		addressWord(d.VerifyingContract),
+	)
+}
+
+// Returns the EIP-712 digest the offerer signs for an order hash
+func (d Domain) Digest(orderHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, d.Separator().Bytes(), orderHash[:])
+}
+
+// Returns the order hash, identical to Seaport.getOrderHash
+func Hash(c *abi.OrderComponents) common.Hash {
+	offer := make([]byte, 0, len(c.Offer)*32)
+	for _, item := range c.Offer {
+		h := crypto.Keccak256(
+			OfferItemTypeHash[:],
+			uint8Word(item.ItemType),
+			addressWord(item.Token),
+			word(item.IdentifierOrCriteria),
+			word(item.StartAmount),
+			word(item.EndAmount),
+		)
+		offer = append(offer, h...)
+	}
+
+	consideration := make([]byte, 0, len(c.Consideration)*32)
+	for _, item := range c.Consideration {
+		h := crypto.Keccak256(
+			ConsiderationItemTypeHash[:],
+			uint8Word(item.ItemType),
+			addressWord(item.Token),
+			word(item.IdentifierOrCriteria),
+			word(item.StartAmount),
+			word(item.EndAmount),
+			addressWord(item.Recipient),
+		)
+		consideration = append(consideration, h...)
+	}
+
+	return crypto.Keccak256Hash(
+		OrderTypeHash[:],
+		addressWord(c.Offerer),
+		addressWord(c.Zone),
+		crypto.Keccak256(offer),
+		crypto.Keccak256(consideration),
+		uint8Word(c.OrderType),
+		word(c.StartTime),
+		word(c.EndTime),
+		c.ZoneHash[:],
+		word(c.Salt),
+		c.ConduitKey[:],
+		word(c.Counter),
+	)
+}
+
+// Returns the 32 byte abi encoding of a uint256, nil encodes as zero
+func word(x *big.Int) []byte {
+	if x == nil {
+		return make([]byte, 32)
+	}
+
+	return math.U256Bytes(new(big.Int).Set(x))
+}
+
+func uint8Word(x uint8) []byte {
+	w := make([]byte, 32)
+	w[31] = x
+
+	return w
+}
+
+func addressWord(a common.Address) []byte {
+	return common.LeftPadBytes(a[:], 32)
+}
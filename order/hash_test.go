@@ -0,0 +1,298 @@
+package order
+
+import (
+	"fmt"
+	"goport/abi"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gomath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+//go:generate go run ./testdata/fetch.go
+
+// Returns the runtime code of a canonical Seaport deployment, written to testdata by go generate
+func readCode(t *testing.T, version string) []byte {
+	t.Helper()
+
+	name := filepath.Join("testdata", fmt.Sprintf("Seaport-%s.bin", version))
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("%v, run go generate with RPC_URL set to a mainnet node to fetch the Seaport code", err)
+	}
+
+	code, err := hexutil.Decode(strings.TrimSpace(string(b)))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	return code
+}
+
+// Returns a simulated chain with every canonical Seaport deployment at its address. The domain
+// separators are derived from the chain id and address the contracts run at, the constructors that
+// cached them for mainnet do not run.
+func simulatedSeaport(t *testing.T) *backends.SimulatedBackend {
+	t.Helper()
+
+	alloc := core.GenesisAlloc{}
+	for version, address := range abi.SeaportAddresses {
+		alloc[address] = core.GenesisAccount{Code: readCode(t, version), Balance: new(big.Int)}
+	}
+
+	backend := backends.NewSimulatedBackend(alloc, 30_000_000)
+	t.Cleanup(func() { backend.Close() })
+
+	return backend
+}
+
+func TestDomainSeparator(t *testing.T) {
+	backend := simulatedSeaport(t)
+
+	for version, address := range abi.SeaportAddresses {
+		seaport, err := abi.NewSeaportReader(version, address, backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := seaport.Information(&bind.CallOpts{})
+		if err != nil {
+			t.Fatalf("information of Seaport %s: %v", version, err)
+		}
+		if info.Version != version {
+			t.Fatalf("Seaport %s returned version %s", version, info.Version)
+		}
+
+		d := NewDomain(backend.Blockchain().Config().ChainID, info.Version, address)
+		if got := d.Separator(); got != info.DomainSeparator {
+			t.Fatalf("Separator() = %s, Seaport %s returned %s", got.Hex(), version, common.Hash(info.DomainSeparator).Hex())
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	backend := simulatedSeaport(t)
+
+	token := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	recipient := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	root := CriteriaRoot([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	offer := []abi.OfferItem{
+		{ItemType: abi.ItemTypeNative, Token: common.Address{}, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(1e18), EndAmount: big.NewInt(5e17)},
+		{ItemType: abi.ItemTypeERC20, Token: token, IdentifierOrCriteria: big.NewInt(0), StartAmount: max, EndAmount: max},
+		{ItemType: abi.ItemTypeERC721, Token: token, IdentifierOrCriteria: max, StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		{ItemType: abi.ItemTypeERC1155, Token: token, IdentifierOrCriteria: big.NewInt(42), StartAmount: big.NewInt(10), EndAmount: big.NewInt(20)},
+		{ItemType: abi.ItemTypeERC721WithCriteria, Token: token, IdentifierOrCriteria: root.Big(), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		{ItemType: abi.ItemTypeERC1155WithCriteria, Token: token, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(3), EndAmount: big.NewInt(3)},
+	}
+
+	consideration := make([]abi.ConsiderationItem, len(offer))
+	for i, item := range offer {
+		consideration[i] = abi.ConsiderationItem{
+			ItemType:             item.ItemType,
+			Token:                item.Token,
+			IdentifierOrCriteria: item.IdentifierOrCriteria,
+			StartAmount:          item.EndAmount,
+			EndAmount:            item.StartAmount,
+			Recipient:            recipient,
+		}
+	}
+
+	cases := []abi.OrderComponents{
+		{
+			Offerer:       recipient,
+			Offer:         offer[2:3],
+			Consideration: consideration[:1],
+		},
+		{
+			Offerer:       recipient,
+			Zone:          token,
+			Offer:         offer,
+			Consideration: consideration,
+			OrderType:     abi.OrderTypePartialRestricted,
+			StartTime:     big.NewInt(1_700_000_000),
+			EndTime:       max,
+			ZoneHash:      crypto.Keccak256Hash([]byte("zone")),
+			Salt:          big.NewInt(123456789),
+			ConduitKey:    crypto.Keccak256Hash([]byte("conduit")),
+			Counter:       big.NewInt(7),
+		},
+		{
+			Offerer:       token,
+			Offer:         []abi.OfferItem{},
+			Consideration: consideration[3:],
+			Counter:       max,
+		},
+	}
+
+	// getOrderHash takes the same arguments on every version
+	for version, address := range abi.SeaportAddresses {
+		seaport, err := abi.NewSeaportCaller(address, backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, c := range cases {
+			fillZero(&c)
+
+			want, err := seaport.GetOrderHash(&bind.CallOpts{}, c)
+			if err != nil {
+				t.Fatalf("getOrderHash of order %d on Seaport %s: %v", i, version, err)
+			}
+
+			if got := Hash(&c); got != want {
+				t.Fatalf("Hash of order %d = %s, Seaport %s returned %s", i, got.Hex(), version, common.Hash(want).Hex())
+			}
+		}
+	}
+}
+
+// The abi encoder needs every uint256 set
+func fillZero(c *abi.OrderComponents) {
+	for _, n := range []**big.Int{&c.StartTime, &c.EndTime, &c.Salt, &c.Counter} {
+		if *n == nil {
+			*n = new(big.Int)
+		}
+	}
+}
+
+// Compares Hash and Separator against the EIP-712 encoder of go-ethereum
+func TestHashTypedData(t *testing.T) {
+	token := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	c := abi.OrderComponents{
+		Offerer: common.HexToAddress("0x0000000000000000000000000000000000000b0b"),
+		Zone:    token,
+		Offer: []abi.OfferItem{
+			{ItemType: abi.ItemTypeERC721, Token: token, IdentifierOrCriteria: max, StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+			{ItemType: abi.ItemTypeERC1155WithCriteria, Token: token, IdentifierOrCriteria: big.NewInt(5), StartAmount: big.NewInt(2), EndAmount: big.NewInt(3)},
+		},
+		Consideration: []abi.ConsiderationItem{
+			{ItemType: abi.ItemTypeNative, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(1e18), EndAmount: big.NewInt(1e18), Recipient: token},
+			{ItemType: abi.ItemTypeERC20, Token: token, IdentifierOrCriteria: big.NewInt(0), StartAmount: big.NewInt(25), EndAmount: big.NewInt(30), Recipient: token},
+		},
+		OrderType:  abi.OrderTypePartialOpen,
+		StartTime:  big.NewInt(1_700_000_000),
+		EndTime:    max,
+		ZoneHash:   crypto.Keccak256Hash([]byte("zone")),
+		Salt:       big.NewInt(123456789),
+		ConduitKey: crypto.Keccak256Hash([]byte("conduit")),
+		Counter:    big.NewInt(7),
+	}
+
+	offer := make([]interface{}, len(c.Offer))
+	for i, item := range c.Offer {
+		offer[i] = map[string]interface{}{
+			"itemType":             strconv.Itoa(int(item.ItemType)),
+			"token":                item.Token.Hex(),
+			"identifierOrCriteria": item.IdentifierOrCriteria.String(),
+			"startAmount":          item.StartAmount.String(),
+			"endAmount":            item.EndAmount.String(),
+		}
+	}
+
+	consideration := make([]interface{}, len(c.Consideration))
+	for i, item := range c.Consideration {
+		consideration[i] = map[string]interface{}{
+			"itemType":             strconv.Itoa(int(item.ItemType)),
+			"token":                item.Token.Hex(),
+			"identifierOrCriteria": item.IdentifierOrCriteria.String(),
+			"startAmount":          item.StartAmount.String(),
+			"endAmount":            item.EndAmount.String(),
+			"recipient":            item.Recipient.Hex(),
+		}
+	}
+
+	d := NewDomain(big.NewInt(1), "1.5", common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"))
+
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"OrderComponents": {
+				{Name: "offerer", Type: "address"},
+				{Name: "zone", Type: "address"},
+				{Name: "offer", Type: "OfferItem[]"},
+				{Name: "consideration", Type: "ConsiderationItem[]"},
+				{Name: "orderType", Type: "uint8"},
+				{Name: "startTime", Type: "uint256"},
+				{Name: "endTime", Type: "uint256"},
+				{Name: "zoneHash", Type: "bytes32"},
+				{Name: "salt", Type: "uint256"},
+				{Name: "conduitKey", Type: "bytes32"},
+				{Name: "counter", Type: "uint256"},
+			},
+			"OfferItem": {
+				{Name: "itemType", Type: "uint8"},
+				{Name: "token", Type: "address"},
+				{Name: "identifierOrCriteria", Type: "uint256"},
+				{Name: "startAmount", Type: "uint256"},
+				{Name: "endAmount", Type: "uint256"},
+			},
+			"ConsiderationItem": {
+				{Name: "itemType", Type: "uint8"},
+				{Name: "token", Type: "address"},
+				{Name: "identifierOrCriteria", Type: "uint256"},
+				{Name: "startAmount", Type: "uint256"},
+				{Name: "endAmount", Type: "uint256"},
+				{Name: "recipient", Type: "address"},
+			},
+		},
+		PrimaryType: "OrderComponents",
+		Domain: apitypes.TypedDataDomain{
+			Name:              d.Name,
+			Version:           d.Version,
+			ChainId:           (*gomath.HexOrDecimal256)(d.ChainID),
+			VerifyingContract: d.VerifyingContract.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"offerer":       c.Offerer.Hex(),
+			"zone":          c.Zone.Hex(),
+			"offer":         offer,
+			"consideration": consideration,
+			"orderType":     strconv.Itoa(int(c.OrderType)),
+			"startTime":     c.StartTime.String(),
+			"endTime":       c.EndTime.String(),
+			"zoneHash":      c.ZoneHash[:],
+			"salt":          c.Salt.String(),
+			"conduitKey":    c.ConduitKey[:],
+			"counter":       c.Counter.String(),
+		},
+	}
+
+	separator, err := typed.HashStruct("EIP712Domain", typed.Domain.Map())
+	if err != nil {
+		t.Fatalf("HashStruct of the domain: %v", err)
+	}
+
+	if got := d.Separator(); got != common.BytesToHash(separator) {
+		t.Fatalf("Separator() = %s, want %s", got.Hex(), common.BytesToHash(separator).Hex())
+	}
+
+	want, err := typed.HashStruct(typed.PrimaryType, typed.Message)
+	if err != nil {
+		t.Fatalf("HashStruct of the order: %v", err)
+	}
+
+	if got := Hash(&c); got != common.BytesToHash(want) {
+		t.Fatalf("Hash() = %s, want %s", got.Hex(), common.BytesToHash(want).Hex())
+	}
+}
@@ -0,0 +1,178 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Maximum height of a bulk order merkle tree supported by Seaport
+const MaxBulkOrderHeight = 24
+
+// Size of the leaf index encoded between a bulk order signature and its proof
+const bulkOrderKeySize = 3
+
+// Value returned by isValidSignature when an EIP-1271 signature is valid
+var eip1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+var (
+	ErrInvalidSignatureLength = errors.New("invalid order signature length")
+	ErrInvalidSignature       = errors.New("invalid order signature")
+	ErrInvalidSigner          = errors.New("order signer is not the offerer")
+	ErrBadContractSignature   = errors.New("offerer contract rejected the order signature")
+)
+
+// Returns true if the error means the signature is invalid, rather than that it could not be checked
+func IsSignatureError(err error) bool {
+	return errors.Is(err, ErrInvalidSignatureLength) ||
+		errors.Is(err, ErrInvalidSignature) ||
+		errors.Is(err, ErrInvalidSigner) ||
+		errors.Is(err, ErrBadContractSignature)
+}
+
+// Returns true if the domain is a Seaport version that accepts bulk order signatures
+func (d Domain) SupportsBulkOrders() bool {
+	return d.Version != "1.0" && d.Version != "1.1"
+}
+
+// Returns the EIP-712 type hash of a bulk order tree of the given height
+func BulkOrderTypeHash(height int) common.Hash {
+	bulkOrderType := "BulkOrder(OrderComponents" + strings.Repeat("[2]", height) + " tree)"
+
+	return crypto.Keccak256Hash([]byte(bulkOrderType + considerationItemType + offerItemType + orderComponentsType))
+}
+
+// Verifies that the offerer signed the order hash, the same way Seaport does on fulfillment.
+// Signatures of 65 bytes or 64 bytes compact (EIP-2098) are recovered first, and if they do not
+// recover to the offerer an offerer with code is asked through EIP-1271, with the original digest
+// and signature. Seaport 1.1 asks offerers with code first and never recovers their signatures.
+// Bulk order signatures are accepted when the domain supports them.
+func VerifySignature(ctx context.Context, caller bind.ContractCaller, d Domain, offerer common.Address, orderHash common.Hash, signature []byte) error {
+	digest := d.Digest(orderHash)
+	originalDigest := digest
+	sig := signature
+
+	if !d.SupportsBulkOrders() {
+		code, err := caller.CodeAt(ctx, offerer, nil)
+		if err != nil {
+			return err
+		}
+
+		if len(code) > 0 {
+			return verifyContractSignature(ctx, caller, offerer, digest, signature)
+		}
+	} else if height, ok := bulkOrderHeight(len(signature)); ok {
+		var root common.Hash
+		sig, root = bulkOrderRoot(signature, orderHash, height)
+		digest = d.Digest(crypto.Keccak256Hash(BulkOrderTypeHash(height).Bytes(), root[:]))
+	}
+
+	signer, recoverErr := recoverSigner(digest, sig)
+	if recoverErr == nil && signer == offerer {
+		return nil
+	}
+
+	if d.SupportsBulkOrders() {
+		code, err := caller.CodeAt(ctx, offerer, nil)
+		if err != nil {
+			return err
+		}
+
+		// Contract offerers validate the original digest and signature themselves
+		if len(code) > 0 {
+			return verifyContractSignature(ctx, caller, offerer, originalDigest, signature)
+		}
+	}
+
+	if recoverErr != nil {
+		return recoverErr
+	}
+
+	return ErrInvalidSigner
+}
+
+// Recovers the signer of a digest from a 65 byte or 64 byte compact signature
+func recoverSigner(digest common.Hash, sig []byte) (common.Address, error) {
+	var rsv [65]byte
+
+	switch len(sig) {
+	case 65:
+		copy(rsv[:], sig)
+		if sig[64] != 27 && sig[64] != 28 {
+			return common.Address{}, ErrInvalidSignature
+		}
+		rsv[64] = sig[64] - 27
+	case 64:
+		// EIP-2098 packs the parity of v into the top bit of s
+		copy(rsv[:64], sig)
+		rsv[64] = rsv[32] >> 7
+		rsv[32] &= 0x7f
+	default:
+		return common.Address{}, ErrInvalidSignatureLength
+	}
+
+	pub, err := crypto.SigToPub(digest[:], rsv[:])
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Calls isValidSignature on a contract offerer
+func verifyContractSignature(ctx context.Context, caller bind.ContractCaller, offerer common.Address, digest common.Hash, sig []byte) error {
+	c, err := abi.NewERC1271Caller(offerer, caller)
+	if err != nil {
+		return err
+	}
+
+	magic, err := c.IsValidSignature(&bind.CallOpts{Context: ctx}, digest, sig)
+	if err != nil || magic != eip1271MagicValue {
+		return ErrBadContractSignature
+	}
+
+	return nil
+}
+
+// Returns the height of the bulk order tree if the signature length matches a bulk order signature
+func bulkOrderHeight(length int) (int, bool) {
+	for _, sigLength := range []int{64, 65} {
+		proofLength := length - sigLength - bulkOrderKeySize
+		if proofLength <= 0 || proofLength%32 != 0 {
+			continue
+		}
+
+		if height := proofLength / 32; height <= MaxBulkOrderHeight {
+			return height, true
+		}
+	}
+
+	return 0, false
+}
+
+// Splits a bulk order signature into the signature itself and the merkle root of the tree the order is in
+func bulkOrderRoot(signature []byte, leaf common.Hash, height int) ([]byte, common.Hash) {
+	sigLength := len(signature) - bulkOrderKeySize - height*32
+	key := new(big.Int).SetBytes(signature[sigLength : sigLength+bulkOrderKeySize]).Uint64()
+	proof := signature[sigLength+bulkOrderKeySize:]
+
+	node := leaf
+	for i := 0; i < height; i++ {
+		sibling := proof[i*32 : (i+1)*32]
+
+		// The bit of the key at each level tells whether the node is the right child
+		if (key>>i)&1 == 1 {
+			node = crypto.Keccak256Hash(sibling, node[:])
+		} else {
+			node = crypto.Keccak256Hash(node[:], sibling)
+		}
+	}
+
+	return signature[:sigLength], node
+}
@@ -0,0 +1,140 @@
+package order
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"goport/abi"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Returns the code of an EIP-1271 contract accepting any signature of one digest. It returns the
+// magic value if the hash argument of isValidSignature is the digest, and reverts otherwise:
+//
+//	PUSH1 4 CALLDATALOAD PUSH32 digest EQ PUSH1 ok JUMPI PUSH1 0 PUSH1 0 REVERT
+//	ok: JUMPDEST PUSH32 magic PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+func eip1271Code(digest common.Hash) []byte {
+	code := []byte{0x60, 0x04, 0x35, 0x7f}
+	code = append(code, digest[:]...)
+	code = append(code, 0x14, 0x60, 0x2d, 0x57, 0x60, 0x00, 0x60, 0x00, 0xfd, 0x5b, 0x7f)
+	code = append(code, eip1271MagicValue[:]...)
+	code = append(code, make([]byte, 28)...)
+	code = append(code, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3)
+
+	return code
+}
+
+// Signs a digest with a 65 byte signature, v is 27 or 28 like Seaport expects
+func signDigest(t *testing.T, key *ecdsa.PrivateKey, digest common.Hash) []byte {
+	t.Helper()
+
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+
+	return sig
+}
+
+// Returns the EIP-2098 compact form of a 65 byte signature
+func compact(sig []byte) []byte {
+	c := append([]byte{}, sig[:64]...)
+	if sig[64] == 28 {
+		c[32] |= 0x80
+	}
+
+	return c
+}
+
+// Returns a bulk order signature of the order at index 1 of a tree of height 2
+func signBulkOrder(t *testing.T, key *ecdsa.PrivateKey, d Domain, orderHash common.Hash) []byte {
+	t.Helper()
+
+	proof := []common.Hash{crypto.Keccak256Hash([]byte("left")), crypto.Keccak256Hash([]byte("right"))}
+
+	// The order is the right child at the first level and the left child at the second
+	root := crypto.Keccak256Hash(proof[0][:], orderHash[:])
+	root = crypto.Keccak256Hash(root[:], proof[1][:])
+
+	sig := signDigest(t, key, d.Digest(crypto.Keccak256Hash(BulkOrderTypeHash(2).Bytes(), root[:])))
+	sig = append(sig, 0, 0, 1)
+
+	return append(sig, append(proof[0][:], proof[1][:]...)...)
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	// A delegated account (EIP-7702) has code that rejects everything but still signs with its key
+	delegatedKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegated := crypto.PubkeyToAddress(delegatedKey.PublicKey)
+
+	d := NewDomain(big.NewInt(1), abi.SeaportV1_5, abi.SeaportAddresses[abi.SeaportV1_5])
+	legacy := NewDomain(big.NewInt(1), abi.SeaportV1_1, abi.SeaportAddresses[abi.SeaportV1_1])
+	orderHash := crypto.Keccak256Hash([]byte("order"))
+
+	wallet := common.HexToAddress("0x0000000000000000000000000000000000001271")
+	legacyWallet := common.HexToAddress("0x0000000000000000000000000000000000001111")
+
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		wallet:       {Code: eip1271Code(d.Digest(orderHash)), Balance: new(big.Int)},
+		legacyWallet: {Code: eip1271Code(legacy.Digest(orderHash)), Balance: new(big.Int)},
+		delegated:    {Code: []byte{0x60, 0x00, 0x60, 0x00, 0xfd}, Balance: new(big.Int)},
+	}, 30_000_000)
+	t.Cleanup(func() { backend.Close() })
+
+	sig := signDigest(t, key, d.Digest(orderHash))
+	bulk := signBulkOrder(t, key, d, orderHash)
+
+	badV := append([]byte{}, sig...)
+	badV[64] = 29
+
+	zeroS := append([]byte{}, sig...)
+	copy(zeroS[32:64], make([]byte, 32))
+
+	cases := []struct {
+		name      string
+		domain    Domain
+		offerer   common.Address
+		signature []byte
+		err       error
+	}{
+		{"65 bytes", d, signer, sig, nil},
+		{"compact 64 bytes", d, signer, compact(sig), nil},
+		{"bulk order", d, signer, bulk, nil},
+		{"compact bulk order", d, signer, append(compact(bulk[:65]), bulk[65:]...), nil},
+		{"bulk order on 1.1", legacy, signer, signBulkOrder(t, key, legacy, orderHash), ErrInvalidSignatureLength},
+		{"other domain", legacy, signer, sig, ErrInvalidSigner},
+		{"EIP-1271 of another digest", d, legacyWallet, sig, ErrBadContractSignature},
+		{"other EOA", d, common.HexToAddress("0x0000000000000000000000000000000000000b0b"), sig, ErrInvalidSigner},
+		{"bad v", d, signer, badV, ErrInvalidSignature},
+		{"zero s", d, signer, zeroS, ErrInvalidSignature},
+		{"bad length", d, signer, sig[:63], ErrInvalidSignatureLength},
+		{"EIP-1271", d, wallet, []byte{1, 2, 3}, nil},
+		{"EIP-1271 with a bulk order signature", d, wallet, bulk, nil},
+		{"EIP-1271 on 1.1", legacy, legacyWallet, sig, nil},
+		{"delegated account", d, delegated, signDigest(t, delegatedKey, d.Digest(orderHash)), nil},
+		{"delegated account on 1.1", legacy, delegated, signDigest(t, delegatedKey, legacy.Digest(orderHash)), ErrBadContractSignature},
+	}
+
+	for _, tc := range cases {
+		err := VerifySignature(context.Background(), backend, tc.domain, tc.offerer, orderHash, tc.signature)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: VerifySignature = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+//go:build ignore
+
+// Writes the runtime code of the canonical Seaport deployments to testdata, for the hash tests to
+// install on a simulated chain. Reads the code from the mainnet node at RPC_URL:
+//
+//	RPC_URL=https://... go generate ./order
+package main
+
+import (
+	"context"
+	"fmt"
+	"goport/abi"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func main() {
+	url := os.Getenv("RPC_URL")
+	if url == "" {
+		log.Fatal("RPC_URL is required")
+	}
+
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", url, err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	for version, address := range abi.SeaportAddresses {
+		code, err := client.CodeAt(ctx, address, nil)
+		if err != nil {
+			log.Fatalf("Failed to read the code of Seaport %s: %v", version, err)
+		}
+		if len(code) == 0 {
+			log.Fatalf("Seaport %s is not deployed at %s", version, address.Hex())
+		}
+
+		name := filepath.Join("testdata", fmt.Sprintf("Seaport-%s.bin", version))
+		if err := os.WriteFile(name, []byte(hexutil.Encode(code)+"\n"), 0644); err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Wrote %d bytes of Seaport %s to %s", len(code), version, name)
+	}
+}
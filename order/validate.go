@@ -0,0 +1,242 @@
+package order
+
+import (
+	"context"
+	"goport/abi"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Reason an order cannot be fulfilled
+type ErrorCode string
+
+const (
+	CodeNoItems             ErrorCode = "no-items"
+	CodeZeroAmount          ErrorCode = "zero-amount"
+	CodeInvalidERC721Amount ErrorCode = "invalid-erc721-amount"
+	CodeInvalidTime         ErrorCode = "invalid-time"
+	CodeNotStarted          ErrorCode = "not-started"
+	CodeExpired             ErrorCode = "expired"
+	CodeInvalidSignature    ErrorCode = "invalid-signature"
+	CodeCancelled           ErrorCode = "cancelled"
+	CodeFilled              ErrorCode = "filled"
+	CodeStaleCounter        ErrorCode = "stale-counter"
+)
+
+// Outcome of validating an order against its own fields and the Seaport contract
+type ValidationResult struct {
+	Hash   common.Hash
+	Errors []ErrorCode
+
+	// On-chain status of the order
+	IsValidated bool
+	IsCancelled bool
+	TotalFilled *big.Int
+	TotalSize   *big.Int
+}
+
+// Returns true if no check failed
+func (r *ValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Returns true if the check with the given code failed
+func (r *ValidationResult) Has(code ErrorCode) bool {
+	for _, c := range r.Errors {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error returned for an order that failed validation
+type ValidationError struct {
+	Result *ValidationResult
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, len(e.Result.Errors))
+	for i, c := range e.Result.Errors {
+		codes[i] = string(c)
+	}
+
+	return "order " + e.Result.Hash.Hex() + " failed validation: " + strings.Join(codes, ", ")
+}
+
+func (r *ValidationResult) add(code ErrorCode) {
+	if !r.Has(code) {
+		r.Errors = append(r.Errors, code)
+	}
+}
+
+// Validates orders against a Seaport deployment
+type Validator struct {
+	Domain    Domain
+	caller    bind.ContractCaller
+	seaport   abi.SeaportReader
+	multicall *abi.Multicall3Caller
+	conduits  *conduitResolver
+
+	// Returns the current time, orders are checked against it for their start and end time
+	Now func() time.Time
+}
+
+// Creates a new Validator for the Seaport deployment of the domain
+func NewValidator(caller bind.ContractCaller, domain Domain) (*Validator, error) {
+	s, err := abi.NewSeaportReader(domain.Version, domain.VerifyingContract, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := abi.NewMulticall3Caller(Multicall3Address, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		Domain:    domain,
+		caller:    caller,
+		seaport:   s,
+		multicall: m,
+		conduits: &conduitResolver{
+			seaport:  s,
+			caller:   caller,
+			conduits: make(map[[32]byte]common.Address),
+		},
+		Now: time.Now,
+	}, nil
+}
+
+// Runs every check on an order. Failed checks are reported in the result, the error is only set
+// when the chain could not be queried.
+func (v *Validator) Validate(ctx context.Context, c *abi.OrderComponents, signature []byte) (*ValidationResult, error) {
+	return v.validate(ctx, c, signature, false)
+}
+
+// Runs every check on an order whose signature Validators.ForOrder verified for the domain of the
+// validator, except for the signature check
+func (v *Validator) ValidateSigned(ctx context.Context, c *abi.OrderComponents) (*ValidationResult, error) {
+	return v.validate(ctx, c, nil, true)
+}
+
+func (v *Validator) validate(ctx context.Context, c *abi.OrderComponents, signature []byte, signed bool) (*ValidationResult, error) {
+	r := &ValidationResult{Hash: Hash(c)}
+
+	v.validateItems(r, c)
+	v.validateTime(r, c)
+
+	if err := v.validateStatus(ctx, r, c); err != nil {
+		return nil, err
+	}
+
+	// Orders validated on-chain no longer need a signature
+	if !signed && !r.IsValidated && c.OrderType != abi.OrderTypeContract {
+		if err := v.validateSignature(ctx, r, c, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	// Contract offerers generate their offer on fulfillment
+	if c.OrderType != abi.OrderTypeContract {
+		if err := v.validateBalances(ctx, r, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (v *Validator) validateItems(r *ValidationResult, c *abi.OrderComponents) {
+	if len(c.Offer) == 0 && len(c.Consideration) == 0 {
+		r.add(CodeNoItems)
+	}
+
+	check := func(itemType uint8, start *big.Int, end *big.Int) {
+		if start.Sign() == 0 && end.Sign() == 0 {
+			r.add(CodeZeroAmount)
+		}
+
+		one := big.NewInt(1)
+		if (itemType == abi.ItemTypeERC721 || itemType == abi.ItemTypeERC721WithCriteria) && (start.Cmp(one) != 0 || end.Cmp(one) != 0) {
+			r.add(CodeInvalidERC721Amount)
+		}
+	}
+
+	for _, item := range c.Offer {
+		check(item.ItemType, item.StartAmount, item.EndAmount)
+	}
+
+	for _, item := range c.Consideration {
+		check(item.ItemType, item.StartAmount, item.EndAmount)
+	}
+}
+
+func (v *Validator) validateTime(r *ValidationResult, c *abi.OrderComponents) {
+	if c.StartTime.Cmp(c.EndTime) >= 0 {
+		r.add(CodeInvalidTime)
+		return
+	}
+
+	now := big.NewInt(v.Now().Unix())
+
+	if now.Cmp(c.StartTime) < 0 {
+		r.add(CodeNotStarted)
+	}
+
+	if now.Cmp(c.EndTime) >= 0 {
+		r.add(CodeExpired)
+	}
+}
+
+func (v *Validator) validateStatus(ctx context.Context, r *ValidationResult, c *abi.OrderComponents) error {
+	opts := &bind.CallOpts{Context: ctx}
+
+	status, err := v.seaport.GetOrderStatus(opts, r.Hash)
+	if err != nil {
+		return err
+	}
+
+	r.IsValidated = status.IsValidated
+	r.IsCancelled = status.IsCancelled
+	r.TotalFilled = status.TotalFilled
+	r.TotalSize = status.TotalSize
+
+	if status.IsCancelled {
+		r.add(CodeCancelled)
+	}
+
+	if status.TotalSize.Sign() > 0 && status.TotalFilled.Cmp(status.TotalSize) >= 0 {
+		r.add(CodeFilled)
+	}
+
+	counter, err := v.seaport.GetCounter(opts, c.Offerer)
+	if err != nil {
+		return err
+	}
+
+	if counter.Cmp(c.Counter) != 0 {
+		r.add(CodeStaleCounter)
+	}
+
+	return nil
+}
+
+func (v *Validator) validateSignature(ctx context.Context, r *ValidationResult, c *abi.OrderComponents, signature []byte) error {
+	err := VerifySignature(ctx, v.caller, v.Domain, c.Offerer, r.Hash, signature)
+
+	switch {
+	case err == nil:
+	case IsSignatureError(err):
+		r.add(CodeInvalidSignature)
+	default:
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package order
+
+import (
+	"goport/abi"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestValidateItemsAmounts(t *testing.T) {
+	token := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+
+	cases := []struct {
+		itemType uint8
+		start    int64
+		end      int64
+		want     ErrorCode
+	}{
+		{abi.ItemTypeERC721, 1, 1, ""},
+		{abi.ItemTypeERC721, 2, 2, CodeInvalidERC721Amount},
+		{abi.ItemTypeERC721, 1, 2, CodeInvalidERC721Amount},
+		{abi.ItemTypeERC721WithCriteria, 1, 1, ""},
+		{abi.ItemTypeERC721WithCriteria, 2, 2, CodeInvalidERC721Amount},
+		{abi.ItemTypeERC721WithCriteria, 0, 1, CodeInvalidERC721Amount},
+		{abi.ItemTypeERC1155, 5, 5, ""},
+		{abi.ItemTypeERC1155WithCriteria, 5, 5, ""},
+		{abi.ItemTypeERC20, 0, 0, CodeZeroAmount},
+	}
+
+	for _, c := range cases {
+		for _, offer := range []bool{true, false} {
+			o := &abi.OrderComponents{}
+			if offer {
+				o.Offer = []abi.OfferItem{{ItemType: c.itemType, Token: token, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(c.start), EndAmount: big.NewInt(c.end)}}
+			} else {
+				o.Consideration = []abi.ConsiderationItem{{ItemType: c.itemType, Token: token, IdentifierOrCriteria: new(big.Int), StartAmount: big.NewInt(c.start), EndAmount: big.NewInt(c.end)}}
+			}
+
+			r := &ValidationResult{}
+			(&Validator{}).validateItems(r, o)
+
+			if c.want == "" && !r.Valid() {
+				t.Fatalf("item type %d amounts %d-%d failed %v", c.itemType, c.start, c.end, r.Errors)
+			}
+
+			if c.want != "" && !r.Has(c.want) {
+				t.Fatalf("item type %d amounts %d-%d: errors %v, want %s", c.itemType, c.start, c.end, r.Errors, c.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNoDeployment = errors.New("no Seaport deployment to validate orders against")
+
+// Validators of the Seaport deployments followed by the node
+type Validators []*Validator
+
+// Returns the validator of the deployment at the given address, or nil if it is not followed
+func (vs Validators) ForContract(address common.Address) *Validator {
+	for _, v := range vs {
+		if v.Domain.VerifyingContract == address {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Returns the validator of the deployment an order was signed for. The order hash does not depend on
+// the deployment, only the signature does, so the first domain the signature verifies for is used.
+// Orders need no signature once they were validated on chain, and contract orders never have one,
+// those are matched with the first deployment that validated them or that supports contract orders.
+// The signature error is returned when none of them match, the order is then checked with
+// ValidateSigned so the signature is not verified again.
+func (vs Validators) ForOrder(ctx context.Context, c *abi.OrderComponents, signature []byte) (*Validator, error) {
+	if len(vs) == 0 {
+		return nil, ErrNoDeployment
+	}
+
+	if c.OrderType == abi.OrderTypeContract {
+		for _, v := range vs {
+			if v.Domain.SupportsContractOrders() {
+				return v, nil
+			}
+		}
+
+		return nil, ErrNoDeployment
+	}
+
+	hash := Hash(c)
+
+	var sigErr error
+
+	for _, v := range vs {
+		err := VerifySignature(ctx, v.caller, v.Domain, c.Offerer, hash, signature)
+
+		switch {
+		case err == nil:
+			return v, nil
+		case IsSignatureError(err):
+			sigErr = err
+		default:
+			return nil, err
+		}
+
+		status, err := v.seaport.GetOrderStatus(&bind.CallOpts{Context: ctx}, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.IsValidated {
+			return v, nil
+		}
+	}
+
+	return nil, sigErr
+}
+
+// Returns true if the domain is a Seaport version that fulfills contract orders
+func (d Domain) SupportsContractOrders() bool {
+	return d.Version != "1.0" && d.Version != "1.1"
+}
@@ -0,0 +1,134 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"goport/abi"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Chain where every account is an EOA, counting the code lookups of signature checks. Seaport only
+// answers getOrderStatus, with the orders in validated validated on chain.
+type eoaCaller struct {
+	lookups   int
+	validated map[common.Hash]bool
+}
+
+func (c *eoaCaller) CodeAt(ctx context.Context, account common.Address, block *big.Int) ([]byte, error) {
+	c.lookups++
+	return nil, nil
+}
+
+func (c *eoaCaller) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	seaport, err := abi.SeaportMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := seaport.MethodById(call.Data)
+	if err != nil || m.Name != "getOrderStatus" {
+		return nil, errors.New("no contracts")
+	}
+
+	args, err := m.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	hash := common.Hash(args[0].([32]byte))
+
+	return m.Outputs.Pack(c.validated[hash], false, new(big.Int), new(big.Int))
+}
+
+func testValidators(t *testing.T, caller *eoaCaller, versions ...string) Validators {
+	vs := Validators{}
+
+	for i, version := range versions {
+		v, err := NewValidator(caller, NewDomain(big.NewInt(1), version, common.BigToAddress(big.NewInt(int64(i+1)))))
+		if err != nil {
+			t.Fatalf("NewValidator: %v", err)
+		}
+
+		vs = append(vs, v)
+	}
+
+	return vs
+}
+
+func TestForOrder(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &abi.OrderComponents{Offerer: crypto.PubkeyToAddress(key.PublicKey)}
+
+	sign := func(d Domain) []byte {
+		digest := d.Digest(Hash(c))
+
+		sig, err := crypto.Sign(digest[:], key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig[64] += 27
+
+		return sig
+	}
+
+	caller := &eoaCaller{}
+	vs := testValidators(t, caller, abi.SeaportV1_1, abi.SeaportV1_5, abi.SeaportV1_6)
+
+	v, err := vs.ForOrder(context.Background(), c, sign(vs[1].Domain))
+	if err != nil {
+		t.Fatalf("ForOrder: %v", err)
+	}
+	if v != vs[1] {
+		t.Fatalf("ForOrder returned the validator of %s, want %s", v.Domain.VerifyingContract.Hex(), vs[1].Domain.VerifyingContract.Hex())
+	}
+	// Seaport 1.1 looks up the code of the offerer, 1.5 recovers it and 1.6 is not tried
+	if caller.lookups != 1 {
+		t.Fatalf("looked up the offerer code %d times, want 1", caller.lookups)
+	}
+
+	// A signature for a deployment that is not followed is not attributed to any of them
+	other := NewDomain(big.NewInt(1), abi.SeaportV1_5, common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"))
+
+	if _, err := vs.ForOrder(context.Background(), c, sign(other)); !errors.Is(err, ErrInvalidSigner) {
+		t.Fatalf("ForOrder of a signature for another deployment: %v, want %v", err, ErrInvalidSigner)
+	}
+
+	if _, err := vs.ForOrder(context.Background(), c, []byte{1, 2, 3}); !IsSignatureError(err) {
+		t.Fatalf("ForOrder of a malformed signature: %v, want a signature error", err)
+	}
+
+	// Orders validated on chain need no signature
+	caller.validated = map[common.Hash]bool{Hash(c): true}
+	for _, sig := range [][]byte{nil, {1, 2, 3}} {
+		if v, err := vs.ForOrder(context.Background(), c, sig); err != nil || v != vs[0] {
+			t.Fatalf("ForOrder of a validated order with signature %x: %v, want the first deployment", sig, err)
+		}
+	}
+	caller.validated = nil
+
+	if _, err := vs.ForOrder(context.Background(), c, nil); !IsSignatureError(err) {
+		t.Fatalf("ForOrder of an order without signature: %v, want a signature error", err)
+	}
+
+	// Contract orders are never signed, Seaport 1.1 does not support them
+	contract := *c
+	contract.OrderType = abi.OrderTypeContract
+	if v, err := vs.ForOrder(context.Background(), &contract, nil); err != nil || v != vs[1] {
+		t.Fatalf("ForOrder of a contract order: %v, want the 1.5 deployment", err)
+	}
+	if _, err := vs[:1].ForOrder(context.Background(), &contract, nil); !errors.Is(err, ErrNoDeployment) {
+		t.Fatalf("ForOrder of a contract order on 1.1: %v, want %v", err, ErrNoDeployment)
+	}
+
+	if _, err := (Validators{}).ForOrder(context.Background(), c, sign(other)); !errors.Is(err, ErrNoDeployment) {
+		t.Fatalf("ForOrder without deployments: %v, want %v", err, ErrNoDeployment)
+	}
+}